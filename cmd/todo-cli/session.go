@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// session is the saved sign-in state todo-cli reuses across invocations,
+// so a user only has to run "login" once per server.
+type session struct {
+	Server string `json:"server"`
+	Token  string `json:"token"`
+}
+
+// configPath returns where the session is stored, creating its parent
+// directory if needed.
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("find config dir: %w", err)
+	}
+	dir = filepath.Join(dir, "go_final_project")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create config dir: %w", err)
+	}
+	return filepath.Join(dir, "todo-cli.json"), nil
+}
+
+func saveSession(sess session) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func loadSession() (session, error) {
+	path, err := configPath()
+	if err != nil {
+		return session{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return session{}, fmt.Errorf("not logged in")
+		}
+		return session{}, fmt.Errorf("read session: %w", err)
+	}
+	var sess session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return session{}, fmt.Errorf("decode session: %w", err)
+	}
+	return sess, nil
+}
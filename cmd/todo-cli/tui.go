@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ngavrilenkov/go_final_project/pkg/client"
+)
+
+// runTUI launches the interactive terminal UI: a scrollable, searchable
+// list of tasks that can be completed or edited without leaving the
+// keyboard. It reuses the same saved session as the other todo-cli
+// subcommands.
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	m := newTUIModel(c)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return fmt.Errorf("run tui: %w", err)
+	}
+	if m, ok := final.(tuiModel); ok && m.err != nil {
+		return m.err
+	}
+	return nil
+}
+
+// taskItem adapts client.Task to the bubbles list.Item interface.
+type taskItem struct {
+	task client.Task
+}
+
+func (i taskItem) Title() string { return i.task.Title }
+func (i taskItem) Description() string {
+	if i.task.Repeat == "" {
+		return i.task.Date
+	}
+	return fmt.Sprintf("%s  repeat: %s", i.task.Date, i.task.Repeat)
+}
+func (i taskItem) FilterValue() string { return i.task.Title + " " + i.task.Comment }
+
+type tuiMode int
+
+const (
+	modeList tuiMode = iota
+	modeEdit
+)
+
+// tuiModel is the top-level bubbletea model: a task list, plus a small
+// form used to edit the selected task's title.
+type tuiModel struct {
+	client *client.Client
+	list   list.Model
+	mode   tuiMode
+	edit   textinput.Model
+	editID string
+	status string
+	err    error
+}
+
+func newTUIModel(c *client.Client) tuiModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "tasks"
+	l.SetShowHelp(true)
+
+	ti := textinput.New()
+	ti.Placeholder = "new title"
+
+	return tuiModel{client: c, list: l, edit: ti}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return m.loadTasks("")
+}
+
+type tasksLoadedMsg struct {
+	tasks []client.Task
+	err   error
+}
+
+func (m tuiModel) loadTasks(search string) tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := m.client.GetTasks(context.Background(), search)
+		return tasksLoadedMsg{tasks: tasks, err: err}
+	}
+}
+
+type taskDoneMsg struct{ err error }
+
+func (m tuiModel) doTask(id string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.client.DoTask(context.Background(), id)
+		return taskDoneMsg{err: err}
+	}
+}
+
+type taskRenamedMsg struct{ err error }
+
+func (m tuiModel) renameTask(t client.Task) tea.Cmd {
+	return func() tea.Msg {
+		err := m.client.UpdateTask(context.Background(), t)
+		return taskRenamedMsg{err: err}
+	}
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := lipgloss.NewStyle().GetFrameSize()
+		m.list.SetSize(msg.Width-h, msg.Height-v)
+		return m, nil
+
+	case tasksLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		items := make([]list.Item, len(msg.tasks))
+		for i, t := range msg.tasks {
+			items[i] = taskItem{task: t}
+		}
+		return m, m.list.SetItems(items)
+
+	case taskDoneMsg:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.status = "marked done"
+		return m, m.loadTasks(m.list.FilterValue())
+
+	case taskRenamedMsg:
+		m.mode = modeList
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.status = "saved"
+		return m, m.loadTasks(m.list.FilterValue())
+
+	case tea.KeyMsg:
+		if m.mode == modeEdit {
+			return m.updateEdit(msg)
+		}
+		return m.updateList(msg)
+	}
+
+	// Anything else (e.g. the list's own async filter-match and spinner
+	// messages) belongs to the list component.
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "d":
+		item, ok := m.list.SelectedItem().(taskItem)
+		if !ok {
+			return m, nil
+		}
+		return m, m.doTask(item.task.ID)
+	case "e":
+		item, ok := m.list.SelectedItem().(taskItem)
+		if !ok {
+			return m, nil
+		}
+		m.mode = modeEdit
+		m.editID = item.task.ID
+		m.edit.SetValue(item.task.Title)
+		m.edit.Focus()
+		return m, textinput.Blink
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeList
+		return m, nil
+	case tea.KeyEnter:
+		item := m.findItem(m.editID)
+		item.Title = m.edit.Value()
+		return m, m.renameTask(item)
+	}
+	var cmd tea.Cmd
+	m.edit, cmd = m.edit.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) findItem(id string) client.Task {
+	for _, li := range m.list.Items() {
+		if ti, ok := li.(taskItem); ok && ti.task.ID == id {
+			return ti.task
+		}
+	}
+	return client.Task{ID: id}
+}
+
+var editTitleStyle = lipgloss.NewStyle().Bold(true).Padding(1, 2)
+
+func (m tuiModel) View() string {
+	if m.mode == modeEdit {
+		return editTitleStyle.Render("edit title (enter to save, esc to cancel)\n\n" + m.edit.View())
+	}
+	view := m.list.View()
+	if m.status != "" {
+		view += "\n" + m.status
+	}
+	return view
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ngavrilenkov/go_final_project/internal/archive"
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+// runArchive exports the entire dataset - tasks, reminder delivery
+// history, logged time entries, task notes and task links - to a single
+// versioned JSON archive, or restores one written by a prior export,
+// the same operations exposed to signed-in owners over
+// /api/admin/db/archive.
+func runArchive(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: archive <export|restore> [flags]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("archive "+sub, flag.ContinueOnError)
+	file := fs.String("file", "", "archive file (default: stdout for export, stdin for restore)")
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configArgs(*configPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	switch sub {
+	case "export":
+		a, err := archive.Export(ctx, store, store, store, store, store)
+		if err != nil {
+			return err
+		}
+
+		w := os.Stdout
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", *file, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(a); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+		if *file != "" {
+			fmt.Fprintf(os.Stderr, "exported %d tasks, %d sent reminders, %d time entries, %d notes and %d links to %s\n", len(a.Tasks), len(a.SentReminders), len(a.TimeEntries), len(a.Notes), len(a.Links), *file)
+		}
+	case "restore":
+		r := os.Stdin
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return fmt.Errorf("open %s: %w", *file, err)
+			}
+			defer f.Close()
+			r = f
+		}
+		var a archive.Archive
+		if err := json.NewDecoder(r).Decode(&a); err != nil {
+			return fmt.Errorf("decode archive: %w", err)
+		}
+		if err := archive.Restore(ctx, store, store, store, store, store, a); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "restored %d tasks, %d sent reminders, %d time entries, %d notes and %d links\n", len(a.Tasks), len(a.SentReminders), len(a.TimeEntries), len(a.Notes), len(a.Links))
+	default:
+		return fmt.Errorf("unknown archive subcommand %q, want export or restore", sub)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ngavrilenkov/go_final_project/internal/backupcrypto"
+)
+
+// runBackup decrypts a scheduled backup written by the encrypted backup
+// worker (internal/backup with a backupcrypto.Cipher configured), the
+// operational counterpart to that worker for operators restoring from
+// an encrypted snapshot.
+func runBackup(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: backup decrypt --in FILE --out FILE (--passphrase PASSPHRASE | --identity AGE-SECRET-KEY-1...)")
+	}
+	sub, args := args[0], args[1:]
+	if sub != "decrypt" {
+		return fmt.Errorf("unknown backup subcommand %q, want decrypt", sub)
+	}
+
+	fs := flag.NewFlagSet("backup decrypt", flag.ContinueOnError)
+	in := fs.String("in", "", "encrypted backup file to decrypt")
+	out := fs.String("out", "", "path to write the decrypted database to")
+	passphrase := fs.String("passphrase", "", "passphrase the backup was encrypted with")
+	passphraseFile := fs.String("passphrase-file", "", "file containing the passphrase, as an alternative to --passphrase")
+	identity := fs.String("identity", "", "age identity (AGE-SECRET-KEY-1...) the backup was encrypted to")
+	identityFile := fs.String("identity-file", "", "file containing the identity, as an alternative to --identity")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" {
+		return fmt.Errorf("--in and --out are required")
+	}
+
+	if *passphraseFile != "" {
+		if *passphrase != "" {
+			return fmt.Errorf("--passphrase and --passphrase-file are mutually exclusive")
+		}
+		v, err := readTrimmedFile(*passphraseFile)
+		if err != nil {
+			return fmt.Errorf("read --passphrase-file: %w", err)
+		}
+		*passphrase = v
+	}
+	if *identityFile != "" {
+		if *identity != "" {
+			return fmt.Errorf("--identity and --identity-file are mutually exclusive")
+		}
+		v, err := readTrimmedFile(*identityFile)
+		if err != nil {
+			return fmt.Errorf("read --identity-file: %w", err)
+		}
+		*identity = v
+	}
+	if (*passphrase == "") == (*identity == "") {
+		return fmt.Errorf("exactly one of --passphrase/--passphrase-file or --identity/--identity-file is required")
+	}
+
+	ciphertext, err := os.ReadFile(*in)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", *in, err)
+	}
+
+	var plaintext []byte
+	if *passphrase != "" {
+		plaintext, err = backupcrypto.DecryptPassphrase(ciphertext, *passphrase)
+	} else {
+		plaintext, err = backupcrypto.DecryptWithIdentity(ciphertext, *identity)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(*out, plaintext, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", *out, err)
+	}
+	fmt.Fprintf(os.Stderr, "decrypted %s to %s\n", *in, *out)
+	return nil
+}
+
+// readTrimmedFile reads a secret from a file, trimming a single
+// trailing newline the way editors and "echo >file" commonly leave one.
+func readTrimmedFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
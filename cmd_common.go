@@ -0,0 +1,19 @@
+package main
+
+import "flag"
+
+// configFlag adds the same --config flag serve accepts to fs, so the
+// operational subcommands (migrate, export, import, user) can point at
+// the same config file to resolve settings like the database path.
+func configFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", "", "path to an optional YAML or TOML config file (see internal/config/file.go for keys)")
+}
+
+// configArgs turns a --config flag's value back into the argument slice
+// config.Load expects.
+func configArgs(configPath string) []string {
+	if configPath == "" {
+		return nil
+	}
+	return []string{"--config", configPath}
+}
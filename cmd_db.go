@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+// runDB runs a maintenance operation against the configured database:
+// integrity-check, vacuum or backup, the same operations exposed to
+// signed-in owners over /api/admin/db.
+func runDB(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: db <integrity-check|vacuum|backup> [flags]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("db "+sub, flag.ContinueOnError)
+	out := fs.String("out", "", "backup destination file (backup only)")
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configArgs(*configPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	var report storage.MaintenanceReport
+	switch sub {
+	case "integrity-check":
+		report, err = store.IntegrityCheck(ctx)
+	case "vacuum":
+		report, err = store.Vacuum(ctx)
+	case "backup":
+		if *out == "" {
+			return fmt.Errorf("--out is required")
+		}
+		report, err = store.Backup(ctx, *out)
+	default:
+		return fmt.Errorf("unknown db subcommand %q, want integrity-check, vacuum or backup", sub)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(report.Message)
+	if !report.OK {
+		return fmt.Errorf("%s reported a problem", sub)
+	}
+	return nil
+}
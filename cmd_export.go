@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+// exportLimit caps how many tasks a single export reads, comfortably
+// above any realistic scheduler database.
+const exportLimit = 1 << 20
+
+// runExport writes every task in the configured database to out (stdout
+// by default) as a JSON array, in the same shape the API returns them.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := fs.String("out", "", "file to write the exported tasks to (default: stdout)")
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configArgs(*configPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer store.Close()
+
+	tasks, err := store.Tasks(context.Background(), "", exportLimit, false, "", nil, "")
+	if err != nil {
+		return fmt.Errorf("list tasks: %w", err)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(tasks); err != nil {
+		return fmt.Errorf("write tasks: %w", err)
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "exported %d tasks to %s\n", len(tasks), *out)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+// runImport reads a JSON array of tasks (in the shape runExport writes,
+// or the API returns) from in (stdin by default) and inserts each one
+// into the configured database as a new task; the ID field, if present,
+// is ignored.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	in := fs.String("in", "", "file to read tasks from (default: stdin)")
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r := os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", *in, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var tasks []storage.Task
+	if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+		return fmt.Errorf("decode tasks: %w", err)
+	}
+
+	cfg, err := config.Load(configArgs(*configPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	for i, t := range tasks {
+		if _, err := store.AddTask(ctx, t); err != nil {
+			return fmt.Errorf("import task %d %q: %w", i, t.Title, err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d tasks\n", len(tasks))
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+// runMigrate ensures the configured SQLite database exists with the
+// current schema. sqlite.Open already creates the schema on first use,
+// so migrating is just that: open it (creating it if needed) and close
+// it again.
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configArgs(*configPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	if err := store.Close(); err != nil {
+		return fmt.Errorf("close database: %w", err)
+	}
+
+	fmt.Printf("database %s is up to date\n", cfg.DBFile)
+	return nil
+}
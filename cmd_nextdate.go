@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+)
+
+// runNextDate computes the next occurrence of a task's repeat rule from
+// the terminal, the same calculation the /api/nextdate endpoint performs
+// for the web UI.
+func runNextDate(args []string) error {
+	fs := flag.NewFlagSet("nextdate", flag.ContinueOnError)
+	now := fs.String("now", "", "reference date in YYYYMMDD format (default: today)")
+	date := fs.String("date", "", "the task's date in YYYYMMDD format")
+	repeat := fs.String("repeat", "", "the task's repeat rule, e.g. \"d 3\" or \"y\"")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *date == "" || *repeat == "" {
+		return fmt.Errorf("--date and --repeat are required")
+	}
+
+	nowTime := time.Now()
+	if *now != "" {
+		parsed, err := nextdate.Parse(*now)
+		if err != nil {
+			return fmt.Errorf("--now: %w", err)
+		}
+		nowTime = parsed
+	}
+
+	next, err := nextdate.Next(nowTime, *date, *repeat)
+	if err != nil {
+		return err
+	}
+	fmt.Println(next)
+	return nil
+}
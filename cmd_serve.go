@@ -0,0 +1,524 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/api"
+	"github.com/ngavrilenkov/go_final_project/internal/api/middleware"
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/autotls"
+	"github.com/ngavrilenkov/go_final_project/internal/backup"
+	"github.com/ngavrilenkov/go_final_project/internal/backupcrypto"
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/dbmaintenance"
+	"github.com/ngavrilenkov/go_final_project/internal/demo"
+	"github.com/ngavrilenkov/go_final_project/internal/errorreporter"
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/googlesync"
+	"github.com/ngavrilenkov/go_final_project/internal/googletasks"
+	"github.com/ngavrilenkov/go_final_project/internal/jobs"
+	"github.com/ngavrilenkov/go_final_project/internal/localcache"
+	"github.com/ngavrilenkov/go_final_project/internal/mailtask"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/notify"
+	"github.com/ngavrilenkov/go_final_project/internal/outbox"
+	"github.com/ngavrilenkov/go_final_project/internal/querydedup"
+	"github.com/ngavrilenkov/go_final_project/internal/ratelimit"
+	"github.com/ngavrilenkov/go_final_project/internal/reminder"
+	"github.com/ngavrilenkov/go_final_project/internal/retention"
+	"github.com/ngavrilenkov/go_final_project/internal/s3upload"
+	"github.com/ngavrilenkov/go_final_project/internal/sdnotify"
+	"github.com/ngavrilenkov/go_final_project/internal/slack"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+	"github.com/ngavrilenkov/go_final_project/internal/taskcache"
+	"github.com/ngavrilenkov/go_final_project/internal/upgrade"
+	"github.com/ngavrilenkov/go_final_project/internal/version"
+)
+
+// runServe runs the scheduler HTTP server until it receives a shutdown
+// signal, handing off to a re-exec'd process on SIGUSR2, reloading a
+// subset of its settings on SIGHUP, and otherwise blocking until the
+// listener stops or the process is asked to exit.
+func runServe(args []string) {
+	log.Printf("go_final_project %s (commit %s, built %s)", version.Version, version.Commit, version.Date)
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+
+	var demoWorker *demo.Worker
+	if cfg.Demo {
+		demoWorker = demo.New(store, store)
+		if err := demoWorker.RunOnce(context.Background()); err != nil {
+			log.Fatalf("seed demo data: %v", err)
+		}
+	}
+
+	var authManager *auth.Manager
+	if cfg.Password != "" {
+		authManager = auth.NewManager(cfg.Password, cfg.JWTSecret)
+	}
+
+	var rateLimiter *ratelimit.Limiter
+	if cfg.RateLimitRPS > 0 {
+		rateLimiter = ratelimit.New(cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
+
+	cors := middleware.CORSConfig{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+		MaxAge:         cfg.CORSMaxAge,
+	}
+	reporter := errorreporter.New(cfg.ErrorReporterDSN)
+	slackNotifier := slack.NewNotifier(cfg.SlackWebhookURL)
+
+	var notifiers notify.Multi
+	if slackNotifier != nil {
+		notifiers = append(notifiers, slackNotifier)
+	}
+	if n := notify.NewWebhook(cfg.NotifyWebhookURL); n != nil {
+		notifiers = append(notifiers, n)
+	}
+	if n := notify.NewEmail(cfg.NotifyEmailHost, cfg.NotifyEmailPort, cfg.NotifyEmailUsername, cfg.NotifyEmailPassword, cfg.NotifyEmailFrom, cfg.NotifyEmailTo); n != nil {
+		notifiers = append(notifiers, n)
+	}
+	if n := notify.NewTelegram(cfg.NotifyTelegramBotToken, cfg.NotifyTelegramChatID); n != nil {
+		notifiers = append(notifiers, n)
+	}
+	if n := notify.NewNtfy(cfg.NotifyNtfyURL); n != nil {
+		notifiers = append(notifiers, n)
+	}
+
+	eventBus, err := events.New(cfg.NATSURL, cfg.NATSSubjectPrefix)
+	if err != nil {
+		log.Fatalf("events: %v", err)
+	}
+	defer eventBus.Close()
+	if len(notifiers) > 0 {
+		eventBus.Subscribe(func(ctx context.Context, event events.Event) {
+			if err := notifiers.Send(ctx, taskEventNotification(event)); err != nil {
+				log.Printf("events: notify %s: %v", event.Type, err)
+			}
+		})
+	}
+
+	outboxDispatcher := outbox.New(store, eventBus, cfg.OutboxPollInterval)
+
+	var dbMaintenanceWorker *dbmaintenance.Worker
+	var healthChecker api.HealthChecker
+	if cfg.DBMaintenanceInterval > 0 {
+		dbMaintenanceWorker = dbmaintenance.New(store, cfg.DBMaintenanceInterval, cfg.DBMaintenanceVacuumPages)
+		healthChecker = dbMaintenanceWorker
+	}
+
+	var taskStore storage.Store = querydedup.New(store)
+	var taskCache *taskcache.Store
+	if cfg.RedisAddr != "" {
+		taskCache = taskcache.New(taskStore, cfg.RedisAddr, cfg.CacheTTL, eventBus)
+		taskStore = taskCache
+	}
+	var localTaskCache *localcache.Store
+	if cfg.LocalCacheSize > 0 {
+		localTaskCache, err = localcache.New(taskStore, cfg.LocalCacheSize, eventBus)
+		if err != nil {
+			log.Fatalf("localcache: %v", err)
+		}
+		taskStore = localTaskCache
+	}
+	var cacheStats api.CacheStats
+	if localTaskCache != nil {
+		cacheStats = localTaskCache
+	}
+
+	retentionWorker := retention.New(retention.Config{
+		ReminderHistoryAge: cfg.RetentionReminderHistoryAge,
+		AuditLogAge:        cfg.RetentionAuditLogAge,
+		TrashAge:           cfg.TrashRetentionAge,
+	}, store)
+
+	server := api.New(taskStore, store, store, store, cfg.SlackSigningSecret, authManager, rateLimiter, cors, cfg.MaxBodyBytes, cfg.RequestTimeout, cfg.TasksDefaultLimit, cfg.TasksMaxLimit, cfg.AccessLogSkipStatic, reporter, cfg.WebDir, cfg.StaticCacheMaxAge, cfg.BasePath, cfg.TrustedProxies, cfg.AllowedIPs, cfg.DeniedIPs, eventBus, healthChecker, cacheStats, store, store, store, store, store, retentionWorker, cfg.BackupDir)
+
+	var mailWorker *mailtask.Worker
+	if cfg.IMAPHost != "" {
+		mailWorker, err = mailtask.New(mailtask.Config{
+			Host:         cfg.IMAPHost,
+			Username:     cfg.IMAPUsername,
+			Password:     cfg.IMAPPassword,
+			Mailbox:      cfg.IMAPMailbox,
+			PollInterval: cfg.IMAPPollInterval,
+		}, store)
+		if err != nil {
+			log.Fatalf("mailtask: %v", err)
+		}
+	}
+
+	var googleSyncWorker *googlesync.Worker
+	if cfg.GoogleClientID != "" && cfg.GoogleClientSecret != "" && cfg.GoogleRefreshToken != "" {
+		client := googletasks.NewClient(cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRefreshToken, cfg.GoogleTaskListID)
+		googleSyncWorker, err = googlesync.New(store, store, client, cfg.GoogleSyncInterval)
+		if err != nil {
+			log.Fatalf("googlesync: %v", err)
+		}
+	}
+
+	var backupWorker *backup.Worker
+	if cfg.BackupDir != "" {
+		var uploader backup.Uploader
+		if cfg.BackupS3Endpoint != "" && cfg.BackupS3Bucket != "" {
+			uploader = s3upload.NewClient(cfg.BackupS3Endpoint, cfg.BackupS3Region, cfg.BackupS3Bucket, cfg.BackupS3Prefix, cfg.BackupS3AccessKey, cfg.BackupS3SecretKey)
+		}
+		var encrypter backup.Encrypter
+		if cfg.BackupEncryptionPassphrase != "" || cfg.BackupEncryptionAgeRecipient != "" {
+			encrypter, err = backupcrypto.New(backupcrypto.Config{
+				Passphrase:   cfg.BackupEncryptionPassphrase,
+				AgeRecipient: cfg.BackupEncryptionAgeRecipient,
+			})
+			if err != nil {
+				log.Fatalf("backupcrypto: %v", err)
+			}
+		}
+		backupWorker, err = backup.New(backup.Config{
+			Dir:            cfg.BackupDir,
+			Interval:       cfg.BackupInterval,
+			RetentionCount: cfg.BackupRetentionCount,
+			RetentionAge:   cfg.BackupRetentionAge,
+		}, store, uploader, encrypter)
+		if err != nil {
+			log.Fatalf("backup: %v", err)
+		}
+	}
+
+	if cfg.PprofEnabled {
+		pprofAddr := ":" + strconv.Itoa(cfg.PprofPort)
+		go func() {
+			log.Printf("pprof listening on %s", pprofAddr)
+			log.Println(http.ListenAndServe(pprofAddr, nil))
+		}()
+	}
+
+	network, addr := "tcp", ":"+strconv.Itoa(cfg.Port)
+	if cfg.ListenNetwork == "unix" {
+		network, addr = "unix", cfg.SocketPath
+	}
+	ln, err := upgrade.Listen(network, addr, cfg.SocketMode)
+	if err != nil {
+		log.Fatalf("listen on %s %s: %v", network, addr, err)
+	}
+	httpServer := &http.Server{Handler: server}
+
+	var serveLn net.Listener = ln
+	var redirectServer *http.Server
+	var tlsManager *autotls.Manager
+	if cfg.TLSDomain != "" {
+		tlsManager = autotls.New(cfg.TLSDomain, cfg.TLSCacheDir)
+		tlsConfig := tlsManager.TLSConfig()
+		if cfg.TLSClientCAFile != "" {
+			caPEM, err := os.ReadFile(cfg.TLSClientCAFile)
+			if err != nil {
+				log.Fatalf("read TLS client CA file: %v", err)
+			}
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(caPEM) {
+				log.Fatalf("parse TLS client CA file %s: no certificates found", cfg.TLSClientCAFile)
+			}
+			tlsConfig.ClientCAs = clientCAs
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		serveLn = tls.NewListener(ln, tlsConfig)
+
+		redirectAddr := ":" + strconv.Itoa(cfg.HTTPRedirectPort)
+		redirectServer = &http.Server{Addr: redirectAddr, Handler: tlsManager.HTTPHandler(nil)}
+		go func() {
+			log.Printf("redirecting http on %s to https", redirectAddr)
+			if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("http redirect listener: %v", err)
+			}
+		}()
+	}
+
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+	upgradeSig := make(chan os.Signal, 1)
+	signal.Notify(upgradeSig, syscall.SIGUSR2)
+	defer signal.Stop(upgradeSig)
+	hupSig := make(chan os.Signal, 1)
+	signal.Notify(hupSig, syscall.SIGHUP)
+	defer signal.Stop(hupSig)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s %s", network, addr)
+		serveErr <- httpServer.Serve(serveLn)
+	}()
+
+	if slackNotifier != nil {
+		go runSlackDailySummary(shutdownCtx, store, slackNotifier, cfg.SlackSummaryHour)
+	}
+	jobScheduler := jobs.New(store)
+	if len(notifiers) > 0 {
+		reminderScheduler := reminder.New(store, store, notifiers, cfg.ReminderDefaultLeadDays)
+		jobScheduler.Register(jobs.Job{Name: "reminder", Interval: cfg.ReminderPollInterval, Run: reminderScheduler.RunOnce})
+	}
+	if backupWorker != nil {
+		jobScheduler.Register(jobs.Job{Name: "backup", Interval: cfg.BackupInterval, Run: backupWorker.RunOnce})
+	}
+	if cfg.RetentionReminderHistoryAge > 0 || cfg.RetentionAuditLogAge > 0 || cfg.TrashRetentionAge > 0 {
+		jobScheduler.Register(jobs.Job{Name: "retention", Interval: cfg.RetentionInterval, Run: retentionWorker.RunOnce})
+	}
+	if demoWorker != nil && cfg.DemoResetInterval > 0 {
+		jobScheduler.Register(jobs.Job{Name: "demo-reset", Interval: cfg.DemoResetInterval, Run: demoWorker.RunOnce})
+	}
+	if cfg.RolloverMode != "" {
+		jobScheduler.Register(jobs.Job{
+			Name:     "rollover",
+			Interval: 24 * time.Hour,
+			NextDue:  func(time.Time) time.Time { return nextHour(time.Now(), 0) },
+			Run:      func(ctx context.Context) error { return runRolloverOnce(ctx, store, cfg.RolloverMode) },
+		})
+	}
+	go jobScheduler.Run(shutdownCtx)
+	if mailWorker != nil {
+		go mailWorker.Run(shutdownCtx)
+	}
+	if googleSyncWorker != nil {
+		go googleSyncWorker.Run(shutdownCtx)
+	}
+	if dbMaintenanceWorker != nil {
+		go dbMaintenanceWorker.Run(shutdownCtx)
+	}
+	go outboxDispatcher.Run(shutdownCtx)
+
+	if err := sdnotify.Ready(); err != nil {
+		log.Printf("sd_notify READY: %v", err)
+	}
+
+runLoop:
+	for {
+		select {
+		case err := <-serveErr:
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+			break runLoop
+		case <-upgradeSig:
+			// Only the raw listener's fd is inheritable, so the child always
+			// re-derives the TLS wrapper (and re-registers for the redirect
+			// port) from cfg itself on startup.
+			sdnotify.Stopping()
+			if proc, err := upgrade.Spawn(ln); err != nil {
+				log.Printf("upgrade: failed to start new process, staying up: %v", err)
+				<-shutdownCtx.Done()
+				drain(httpServer, redirectServer, cfg.ShutdownTimeout)
+			} else {
+				log.Printf("upgrade: handed off listener to pid %d, draining and exiting", proc.Pid)
+				drain(httpServer, redirectServer, cfg.ShutdownTimeout)
+			}
+			break runLoop
+		case <-shutdownCtx.Done():
+			log.Println("shutting down, draining in-flight requests")
+			sdnotify.Stopping()
+			drain(httpServer, redirectServer, cfg.ShutdownTimeout)
+			break runLoop
+		case <-hupSig:
+			reload(args, authManager, rateLimiter, tlsManager)
+		}
+	}
+
+	if localTaskCache != nil {
+		if err := localTaskCache.Close(); err != nil {
+			log.Printf("close local cache: %v", err)
+		}
+	}
+	if taskCache != nil {
+		if err := taskCache.Close(); err != nil {
+			log.Printf("close cache: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		log.Printf("close database: %v", err)
+	}
+}
+
+// reload re-reads settings from the environment (and --config file, if
+// one was given) and applies the subset that can change without a
+// restart: the rate limiter's throughput, the configured password and
+// JWT secret, and, for HTTPS deployments, it discards the cached TLS
+// certificate so the next handshake picks up one replaced on disk.
+// Everything else (listen address, TLS domain, base path, ...) keeps its
+// original value until the next restart.
+func reload(args []string, authManager *auth.Manager, rateLimiter *ratelimit.Limiter, tlsManager *autotls.Manager) {
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Printf("SIGHUP: reload config: %v", err)
+		return
+	}
+	if rateLimiter != nil {
+		rateLimiter.SetLimits(cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
+	if authManager != nil {
+		authManager.SetPassword(cfg.Password)
+		authManager.SetJWTSecret(cfg.JWTSecret)
+	}
+	if tlsManager != nil {
+		tlsManager.Reload()
+	}
+	log.Println("SIGHUP: configuration reloaded")
+}
+
+// drain gives httpServer (and, if TLS is enabled, the HTTP redirect
+// server) up to timeout to finish in-flight requests before forcing
+// remaining connections closed.
+func drain(httpServer, redirectServer *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("graceful shutdown failed, closing connections: %v", err)
+		httpServer.Close()
+	}
+	if redirectServer != nil {
+		redirectServer.Shutdown(ctx)
+	}
+}
+
+// taskEventNotification renders a domain event as a notify.Event for the
+// enabled notification channels. handleDeleteTask publishes TaskDeleted
+// with only the task's ID (deleting doesn't otherwise need to load the
+// row), so that event falls back to identifying the task by ID.
+func taskEventNotification(event events.Event) notify.Event {
+	var verb string
+	switch event.Type {
+	case events.TaskCreated:
+		verb = "создана"
+	case events.TaskUpdated:
+		verb = "изменена"
+	case events.TaskCompleted:
+		verb = "выполнена"
+	case events.TaskDeleted:
+		verb = "удалена"
+	default:
+		verb = string(event.Type)
+	}
+
+	subject := fmt.Sprintf("%q", event.Task.Title)
+	if event.Task.Title == "" {
+		subject = fmt.Sprintf("#%d", event.Task.ID)
+	}
+	return notify.Event{
+		Title: fmt.Sprintf("Задача %s %s", subject, verb),
+		Body:  event.Task.Comment,
+	}
+}
+
+// runSlackDailySummary posts store's task list to notifier once per day,
+// at the first minute the local time reaches hour, until ctx is done.
+func runSlackDailySummary(ctx context.Context, store storage.Store, notifier *slack.Notifier, hour int) {
+	for {
+		wait := time.Until(nextHour(time.Now(), hour))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		tasks, err := store.Tasks(ctx, "", tasksLimit, false, "", nil, "")
+		if err != nil {
+			log.Printf("slack daily summary: list tasks: %v", err)
+			continue
+		}
+		if err := notifier.PostSummary(tasks); err != nil {
+			log.Printf("slack daily summary: %v", err)
+		}
+	}
+}
+
+// tasksLimit caps how many tasks the daily Slack summary lists, matching
+// the API's own listing limit (see internal/api.tasksLimit).
+const tasksLimit = 50
+
+// nextHour returns the next time on or after now that falls at hour:00 in
+// now's location, choosing tomorrow if now is already past hour today.
+func nextHour(now time.Time, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// runRolloverOnce advances recurring tasks that are still overdue as of
+// now. It's registered with jobs.Scheduler to run once a day at local
+// midnight. mode is one of "roll" (advance to the next occurrence),
+// "missed" (advance it the same way but record the occurrence as
+// skipped rather than updated, via storage.Store.SkipTask) or "leave"
+// (take no action beyond logging what was found). Non-repeating tasks
+// are left alone: they have no next occurrence to roll to, and are
+// already covered by api.handleRescheduleOverdue for a one-off catch-up.
+func runRolloverOnce(ctx context.Context, store storage.Store, mode string) error {
+	now := time.Now()
+	tasks, err := store.TasksDueBy(ctx, nextdate.Format(now.AddDate(0, 0, -1)))
+	if err != nil {
+		return fmt.Errorf("list overdue tasks: %w", err)
+	}
+
+	var rolled, missed, skippedNonRepeating int
+	for _, t := range tasks {
+		if t.Repeat == "" {
+			skippedNonRepeating++
+			continue
+		}
+		if mode == "leave" {
+			continue
+		}
+
+		anchor := t.RepeatAnchor
+		if anchor == "" {
+			anchor = t.Date
+		}
+		next, err := nextdate.Next(now, anchor, t.Repeat)
+		if err != nil {
+			log.Printf("rollover: task %d: %v", t.ID, err)
+			continue
+		}
+
+		if mode == "missed" {
+			if err := store.SkipTask(ctx, t, next); err != nil {
+				log.Printf("rollover: skip task %d: %v", t.ID, err)
+				continue
+			}
+			missed++
+			continue
+		}
+
+		t.Date = next
+		t.RepeatAnchor = next
+		if err := store.UpdateTask(ctx, t); err != nil {
+			log.Printf("rollover: update task %d: %v", t.ID, err)
+			continue
+		}
+		rolled++
+	}
+	log.Printf("rollover: mode=%s overdue_recurring=%d rolled=%d missed=%d skipped_non_repeating=%d",
+		mode, len(tasks)-skippedNonRepeating, rolled, missed, skippedNonRepeating)
+	return nil
+}
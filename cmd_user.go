@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/config"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+// runUser manages collaborators: "user add" grants a new collaborator
+// sign-in, "user passwd" changes an existing one's password.
+func runUser(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: user <add|passwd> --username NAME --password PASSWORD [--permission read|write]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("user "+sub, flag.ContinueOnError)
+	username := fs.String("username", "", "collaborator username")
+	password := fs.String("password", "", "collaborator password")
+	permission := fs.String("permission", string(storage.PermissionWrite), "collaborator permission: read or write (add only)")
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" || *password == "" {
+		return fmt.Errorf("--username and --password are required")
+	}
+
+	cfg, err := config.Load(configArgs(*configPath))
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	store, err := sqlite.Open(cfg.DBFile, sqlite.Options{WALAutocheckpoint: cfg.DBWALAutocheckpoint, BusyTimeout: cfg.DBBusyTimeout, ForeignKeys: cfg.DBForeignKeys, MaxOpenConns: cfg.DBMaxOpenConns, MaxIdleConns: cfg.DBMaxIdleConns, ConnMaxLifetime: cfg.DBConnMaxLifetime, SlowQueryThreshold: cfg.DBSlowQueryThreshold, WriteRetryDeadline: cfg.DBWriteRetryDeadline})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	switch sub {
+	case "add":
+		perm := storage.Permission(*permission)
+		if perm != storage.PermissionRead && perm != storage.PermissionWrite {
+			return fmt.Errorf("--permission must be %q or %q", storage.PermissionRead, storage.PermissionWrite)
+		}
+		passwordHash, err := auth.HashPassword(*password)
+		if err != nil {
+			return fmt.Errorf("hash password: %w", err)
+		}
+		id, err := store.CreateCollaborator(ctx, *username, passwordHash, perm)
+		if err != nil {
+			return fmt.Errorf("add collaborator: %w", err)
+		}
+		fmt.Printf("added collaborator %s (id %d, %s)\n", *username, id, perm)
+	case "passwd":
+		c, err := store.CollaboratorByUsername(ctx, *username)
+		if err != nil {
+			return fmt.Errorf("find collaborator %s: %w", *username, err)
+		}
+		passwordHash, err := auth.HashPassword(*password)
+		if err != nil {
+			return fmt.Errorf("hash password: %w", err)
+		}
+		if err := store.UpdateCollaboratorPassword(ctx, c.ID, passwordHash); err != nil {
+			return fmt.Errorf("update collaborator %s: %w", *username, err)
+		}
+		fmt.Printf("updated password for %s\n", *username)
+	default:
+		return fmt.Errorf("unknown user subcommand %q, want add or passwd", sub)
+	}
+	return nil
+}
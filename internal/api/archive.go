@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ngavrilenkov/go_final_project/internal/archive"
+)
+
+// handleExportArchive writes the entire dataset - tasks, reminder
+// delivery history, logged time entries, task notes and task links - as
+// a single versioned JSON archive, for migrating to a different storage
+// backend or restoring elsewhere.
+func (s *Server) handleExportArchive(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	a, err := archive.Export(r.Context(), s.store, s.reminders, s.timeTracker, s.noteStore, s.linkStore)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// handleRestoreArchive loads a JSON archive written by handleExportArchive
+// (or a compatible future backend) back into the database, preserving
+// each task's original ID.
+func (s *Server) handleRestoreArchive(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	var a archive.Archive
+	if !s.decodeJSON(w, r, &a) {
+		return
+	}
+	if err := archive.Restore(r.Context(), s.store, s.reminders, s.timeTracker, s.noteStore, s.linkStore, a); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tasks": len(a.Tasks), "sent_reminders": len(a.SentReminders), "time_entries": len(a.TimeEntries), "notes": len(a.Notes), "links": len(a.Links)})
+}
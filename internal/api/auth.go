@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/ngavrilenkov/go_final_project/internal/api/middleware"
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type signInRequest struct {
+	// Username selects a collaborator sign-in. Left empty, Password is
+	// checked against the owner's configured password instead.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password"`
+}
+
+const (
+	accessCookieName  = "token"
+	refreshCookieName = "refresh_token"
+)
+
+func (s *Server) handleSignIn(w http.ResponseWriter, r *http.Request) {
+	if s.auth == nil {
+		s.writeError(w, r, http.StatusServiceUnavailable, errors.New("аутентификация не настроена"))
+		return
+	}
+
+	ip := clientIP(r)
+	if ok, retryAfter := s.loginLimiter.Allow(ip); !ok {
+		writeTooManyAttempts(w, r, retryAfter)
+		return
+	}
+	if ok, retryAfter := s.loginLimiter.Allow(auth.GlobalLoginKey); !ok {
+		writeTooManyAttempts(w, r, retryAfter)
+		return
+	}
+
+	var req signInRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.Username != "" {
+		s.handleCollaboratorSignIn(w, r, req)
+		return
+	}
+
+	if !s.auth.CheckPassword(req.Password) {
+		s.loginLimiter.RecordFailure(ip)
+		s.loginLimiter.RecordFailure(auth.GlobalLoginKey)
+		s.writeError(w, r, http.StatusUnauthorized, errors.New("неверный пароль"))
+		return
+	}
+	s.loginLimiter.RecordSuccess(ip)
+
+	access, refresh, err := s.issueTokens(r.Context(), "", storage.PermissionWrite)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	setSessionCookies(w, r, access, refresh)
+	writeJSON(w, http.StatusOK, map[string]any{"token": access})
+}
+
+// handleCollaboratorSignIn authenticates someone the owner has shared the
+// task list with, issuing them a session scoped to their granted
+// permission rather than the owner's unrestricted one. The caller,
+// handleSignIn, has already checked the IP and global lockouts; this
+// additionally locks out repeated guesses against this one username, so
+// an attacker can't get around the IP lockout by spreading guesses
+// across many collaborator accounts, or vice versa.
+func (s *Server) handleCollaboratorSignIn(w http.ResponseWriter, r *http.Request, req signInRequest) {
+	ip := clientIP(r)
+	if ok, retryAfter := s.loginLimiter.Allow(req.Username); !ok {
+		writeTooManyAttempts(w, r, retryAfter)
+		return
+	}
+
+	collaborator, err := s.sessions.CollaboratorByUsername(r.Context(), req.Username)
+	if errors.Is(err, storage.ErrCollaboratorNotFound) || (err == nil && !auth.VerifyPassword(req.Password, collaborator.PasswordHash)) {
+		s.loginLimiter.RecordFailure(ip)
+		s.loginLimiter.RecordFailure(auth.GlobalLoginKey)
+		s.loginLimiter.RecordFailure(req.Username)
+		s.writeError(w, r, http.StatusUnauthorized, errors.New("неверный логин или пароль"))
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	s.loginLimiter.RecordSuccess(ip)
+	s.loginLimiter.RecordSuccess(req.Username)
+
+	access, refresh, err := s.issueTokens(r.Context(), collaborator.Username, collaborator.Permission)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	setSessionCookies(w, r, access, refresh)
+	writeJSON(w, http.StatusOK, map[string]any{"token": access})
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if s.auth == nil {
+		s.writeError(w, r, http.StatusServiceUnavailable, errors.New("аутентификация не настроена"))
+		return
+	}
+
+	cookie, err := r.Cookie(refreshCookieName)
+	if err != nil {
+		s.writeError(w, r, http.StatusUnauthorized, errors.New("не найден refresh-токен"))
+		return
+	}
+
+	hash := auth.HashToken(cookie.Value)
+	rt, err := s.sessions.RefreshToken(r.Context(), hash)
+	if err != nil || time.Now().After(rt.ExpiresAt) {
+		s.writeError(w, r, http.StatusUnauthorized, errors.New("недействительный refresh-токен"))
+		return
+	}
+	_ = s.sessions.DeleteRefreshToken(r.Context(), hash)
+
+	access, refresh, err := s.issueTokens(r.Context(), rt.Subject, rt.Permission)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	setSessionCookies(w, r, access, refresh)
+	writeJSON(w, http.StatusOK, map[string]any{"token": access})
+}
+
+func (s *Server) handleSignOut(w http.ResponseWriter, r *http.Request) {
+	if s.auth == nil {
+		s.writeError(w, r, http.StatusServiceUnavailable, errors.New("аутентификация не настроена"))
+		return
+	}
+
+	if cookie, err := r.Cookie(accessCookieName); err == nil {
+		if claims, err := s.auth.ParseAccessToken(cookie.Value); err == nil {
+			_ = s.sessions.RevokeToken(r.Context(), claims.JTI, time.Now().Add(auth.AccessTTL))
+		}
+	}
+	if cookie, err := r.Cookie(refreshCookieName); err == nil {
+		_ = s.sessions.DeleteRefreshToken(r.Context(), auth.HashToken(cookie.Value))
+	}
+
+	clearSessionCookies(w)
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// issueTokens mints a fresh access/refresh pair for subject and
+// permission, and persists the refresh token so it can later be looked up
+// (and revoked, or re-issued for the same identity) by its hash.
+func (s *Server) issueTokens(ctx context.Context, subject string, permission storage.Permission) (access, refresh string, err error) {
+	access, _, err = s.auth.NewAccessToken(subject, string(permission))
+	if err != nil {
+		return "", "", err
+	}
+	refresh, err = auth.NewRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.sessions.SaveRefreshToken(ctx, auth.HashToken(refresh), time.Now().Add(auth.RefreshTTL), subject, permission); err != nil {
+		return "", "", err
+	}
+	return access, refresh, nil
+}
+
+// setSessionCookies sets the access/refresh cookies, marking them Secure
+// whenever the request arrived over TLS - directly, or via a reverse proxy
+// in TrustedProxies that terminated it (see middleware.SchemeFrom) - so a
+// proxied deployment doesn't ship session cookies over plain HTTP.
+func setSessionCookies(w http.ResponseWriter, r *http.Request, access, refresh string) {
+	secure := r.TLS != nil || middleware.SchemeFrom(r.Context()) == "https"
+	http.SetCookie(w, &http.Cookie{
+		Name:     accessCookieName,
+		Value:    access,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   secure,
+		Expires:  time.Now().Add(auth.AccessTTL),
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    refresh,
+		Path:     "/api/refresh",
+		HttpOnly: true,
+		Secure:   secure,
+		Expires:  time.Now().Add(auth.RefreshTTL),
+	})
+}
+
+func clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: accessCookieName, Value: "", Path: "/", MaxAge: -1})
+	http.SetCookie(w, &http.Cookie{Name: refreshCookieName, Value: "", Path: "/api/refresh", MaxAge: -1})
+}
+
+// clientIP returns the requester's address without its port, so it can be
+// used as a login rate-limiting key.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeTooManyAttempts responds with 429 and a Retry-After header
+// reflecting the sign-in lockout still in effect.
+func writeTooManyAttempts(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	writeJSON(w, http.StatusTooManyRequests, errorResponse{
+		Error:     fmt.Sprintf("слишком много попыток входа, повторите через %d с", int(retryAfter.Seconds())+1),
+		RequestID: chimiddleware.GetReqID(r.Context()),
+	})
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type bulkRescheduleResponse struct {
+	ChangedIDs []int64 `json:"changed_ids"`
+}
+
+// handleBulkReschedule moves every task whose date falls within [from,
+// to] forward by shift_days days, or to date, in a single transaction,
+// returning the ids that changed. The request that prompted this
+// endpoint also asked for tag and project filters, but storage.Task has
+// no such fields, so only the date range is implemented; tag or project
+// in the query is rejected outright rather than silently ignored.
+func (s *Server) handleBulkReschedule(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	if q.Get("tag") != "" || q.Get("project") != "" {
+		s.writeError(w, r, http.StatusBadRequest,
+			errors.New("фильтрация по tag и project не поддерживается: у задачи нет таких полей, используйте from и to"))
+		return
+	}
+
+	from, to := q.Get("from"), q.Get("to")
+	if from == "" || to == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("необходимо указать from и to"))
+		return
+	}
+	if _, err := nextdate.Parse(from); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("from представлен в некорректном формате"))
+		return
+	}
+	if _, err := nextdate.Parse(to); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("to представлен в некорректном формате"))
+		return
+	}
+
+	shiftRaw, dateRaw := q.Get("shift_days"), q.Get("date")
+	if (shiftRaw == "") == (dateRaw == "") {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("нужно указать ровно один из параметров: shift_days или date"))
+		return
+	}
+
+	var newDate func(current string) (string, error)
+	switch {
+	case shiftRaw != "":
+		days, err := strconv.Atoi(shiftRaw)
+		if err != nil || days == 0 {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("shift_days должен быть ненулевым числом"))
+			return
+		}
+		newDate = func(current string) (string, error) {
+			parsed, err := nextdate.Parse(current)
+			if err != nil {
+				return "", err
+			}
+			return nextdate.Format(parsed.AddDate(0, 0, days)), nil
+		}
+	default:
+		parsed, err := nextdate.Parse(dateRaw)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, errors.New("date представлен в некорректном формате"))
+			return
+		}
+		fixed := nextdate.Format(parsed)
+		newDate = func(string) (string, error) { return fixed, nil }
+	}
+
+	tasks, err := s.store.TasksInRange(r.Context(), from, to)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	changed := make([]storage.Task, 0, len(tasks))
+	ids := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		next, err := newDate(t.Date)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		t.Date = next
+		changed = append(changed, t)
+		ids = append(ids, t.ID)
+	}
+
+	if err := s.store.RescheduleTasks(r.Context(), changed); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bulkRescheduleResponse{ChangedIDs: ids})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+)
+
+// handleCalendar renders the task list as an iCalendar feed (RFC 5545), so
+// a calendar app can subscribe to it directly instead of using the web UI.
+// Each task becomes a VTODO; one with a repeat rule gets that rule
+// translated into an RRULE (see nextdate.RRule).
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.store.Tasks(r.Context(), "", tasksLimit, false, "", nil, "")
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//go_final_project//scheduler//EN\r\n")
+	b.WriteString("CALSCALE:GREGORIAN\r\n")
+
+	stamp := icsTimestamp(time.Now())
+	for _, t := range tasks {
+		due, err := nextdate.Parse(t.Date)
+		if err != nil {
+			continue
+		}
+
+		b.WriteString("BEGIN:VTODO\r\n")
+		fmt.Fprintf(&b, "UID:task-%d@go-final-project\r\n", t.ID)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", stamp)
+		if t.Time != "" {
+			if dueTime, err := time.Parse("15:04", t.Time); err == nil {
+				due = time.Date(due.Year(), due.Month(), due.Day(), dueTime.Hour(), dueTime.Minute(), 0, 0, due.Location())
+				fmt.Fprintf(&b, "DUE:%s\r\n", due.Format("20060102T150405"))
+			} else {
+				fmt.Fprintf(&b, "DUE;VALUE=DATE:%s\r\n", nextdate.Format(due))
+			}
+		} else {
+			fmt.Fprintf(&b, "DUE;VALUE=DATE:%s\r\n", nextdate.Format(due))
+		}
+		if t.DurationMinutes > 0 {
+			fmt.Fprintf(&b, "DURATION:PT%dM\r\n", t.DurationMinutes)
+		}
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeICSText(t.Title))
+		if t.Comment != "" {
+			fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", escapeICSText(t.Comment))
+		}
+		b.WriteString("STATUS:NEEDS-ACTION\r\n")
+		if t.Repeat != "" {
+			if rrule, err := nextdate.RRule(t.Repeat); err == nil {
+				fmt.Fprintf(&b, "RRULE:%s\r\n", rrule)
+			}
+		}
+		b.WriteString("END:VTODO\r\n")
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="tasks.ics"`)
+	w.Write([]byte(b.String()))
+}
+
+// icsTimestamp renders t as the UTC form of an RFC 5545 date-time, used for
+// DTSTAMP.
+func icsTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeICSText escapes the characters RFC 5545 reserves in TEXT values.
+func escapeICSText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
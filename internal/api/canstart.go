@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// handleCanStartTasks reports every task whose StartDate has been
+// reached, ordered by start date. Unlike /api/tasks/overdue, this looks
+// at StartDate rather than Date, so a long-running task with a distant
+// due date shows up here as soon as it can be picked up, without
+// looking overdue.
+func (s *Server) handleCanStartTasks(w http.ResponseWriter, r *http.Request) {
+	today := nextdate.Format(time.Now())
+
+	tasks, err := s.store.TasksStartableBy(r.Context(), today)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]storage.Task{"tasks": tasks})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type createCollaboratorRequest struct {
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	Permission string `json:"permission"`
+}
+
+type collaboratorResponse struct {
+	ID         int64  `json:"id"`
+	Username   string `json:"username"`
+	Permission string `json:"permission"`
+}
+
+// handleCreateCollaborator grants another person access to the shared
+// task list, at read or write permission. Only the owner can do this: a
+// write collaborator can edit tasks but must not be able to grant or
+// revoke anyone else's access, itself included.
+func (s *Server) handleCreateCollaborator(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+
+	var req createCollaboratorRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Username == "" || req.Password == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("не указан логин или пароль"))
+		return
+	}
+
+	permission := storage.Permission(req.Permission)
+	if permission != storage.PermissionRead && permission != storage.PermissionWrite {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("permission должен быть \"read\" или \"write\""))
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := s.sessions.CreateCollaborator(r.Context(), req.Username, passwordHash, permission)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, collaboratorResponse{ID: id, Username: req.Username, Permission: string(permission)})
+}
+
+func (s *Server) handleListCollaborators(w http.ResponseWriter, r *http.Request) {
+	collaborators, err := s.sessions.ListCollaborators(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make([]collaboratorResponse, 0, len(collaborators))
+	for _, c := range collaborators {
+		resp = append(resp, collaboratorResponse{ID: c.ID, Username: c.Username, Permission: string(c.Permission)})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"collaborators": resp})
+}
+
+func (s *Server) handleDeleteCollaborator(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	collaborators, err := s.sessions.ListCollaborators(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	var username string
+	for _, c := range collaborators {
+		if c.ID == id {
+			username = c.Username
+			break
+		}
+	}
+
+	if err := s.sessions.DeleteCollaborator(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, r, status, err)
+		return
+	}
+	// Revoking their refresh token prevents them from extending access;
+	// their current access token still expires naturally within AccessTTL.
+	if username != "" {
+		_ = s.sessions.DeleteRefreshTokensBySubject(r.Context(), username)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// estimateReportRow compares one task's duration estimate (see
+// storage.Task.DurationMinutes) against the time actually logged
+// against it (see storage.TimeTracker), so a freelancer can see which
+// estimates ran over or under.
+type estimateReportRow struct {
+	TaskID          int64  `json:"task_id"`
+	Title           string `json:"title"`
+	EstimateMinutes int    `json:"estimate_minutes"`
+	ActualMinutes   int    `json:"actual_minutes"`
+	DeltaMinutes    int    `json:"delta_minutes"`
+}
+
+// handleEstimatesReport reports, for every task with a duration
+// estimate, how its logged time compares - one row per task rather than
+// per project, since storage.Task has no project field to group by;
+// this is the closest breakdown the current schema supports. ?format=csv
+// returns the same rows as a CSV file instead of JSON.
+func (s *Server) handleEstimatesReport(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.timeTracker.AllTimeEntries(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	now := time.Now()
+	actual := map[int64]int{}
+	for _, e := range entries {
+		actual[e.TaskID] += e.Minutes(now)
+	}
+
+	var rows []estimateReportRow
+	err = s.store.StreamTasks(r.Context(), func(t storage.Task) error {
+		if t.DurationMinutes == 0 {
+			return nil
+		}
+		a := actual[t.ID]
+		rows = append(rows, estimateReportRow{
+			TaskID:          t.ID,
+			Title:           t.Title,
+			EstimateMinutes: t.DurationMinutes,
+			ActualMinutes:   a,
+			DeltaMinutes:    a - t.DurationMinutes,
+		})
+		return nil
+	})
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		writeEstimatesCSV(w, rows)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tasks": rows})
+}
+
+// writeEstimatesCSV writes rows as a CSV file, one line per task.
+func writeEstimatesCSV(w http.ResponseWriter, rows []estimateReportRow) {
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"task_id", "title", "estimate_minutes", "actual_minutes", "delta_minutes"})
+	for _, row := range rows {
+		_ = cw.Write([]string{
+			strconv.FormatInt(row.TaskID, 10),
+			row.Title,
+			strconv.Itoa(row.EstimateMinutes),
+			strconv.Itoa(row.ActualMinutes),
+			strconv.Itoa(row.DeltaMinutes),
+		})
+	}
+	cw.Flush()
+}
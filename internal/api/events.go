@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+)
+
+// eventStreamBuffer bounds how many undelivered events a slow client's
+// connection queues before new ones are dropped for it, so one stalled
+// client can't grow memory unboundedly or block publishing for everyone
+// else.
+const eventStreamBuffer = 16
+
+// handleEvents streams task lifecycle events (see internal/events) to the
+// client as Server-Sent Events, so the web UI can update in place instead
+// of re-fetching /api/tasks after every change. The stream stays open
+// until the client disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, r, http.StatusInternalServerError, errors.New("потоковая передача не поддерживается"))
+		return
+	}
+
+	ch := make(chan events.Event, eventStreamBuffer)
+	unsubscribe := s.events.Subscribe(func(_ context.Context, event events.Event) {
+		select {
+		case ch <- event:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-ch:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// handleExportNDJSON streams every task as newline-delimited JSON, one
+// object per line, without ever building the full list in memory (see
+// storage.Store.StreamTasks) - suitable for piping a database far larger
+// than /api/tasks' tasksLimit into jq or a backup file.
+func (s *Server) handleExportNDJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	enc := json.NewEncoder(w)
+	err := s.store.StreamTasks(r.Context(), func(t storage.Task) error {
+		return enc.Encode(t)
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// The response may already be partway written, so there's no
+		// well-formed error to send the client at this point; report it
+		// the same way writeError does for a 5xx and move on.
+		s.reporter.Report(err, chimiddleware.GetReqID(r.Context()))
+	}
+}
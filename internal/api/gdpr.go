@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+)
+
+type deleteAllDataRequest struct {
+	// Password re-confirms the owner's identity before an irreversible
+	// wipe, the same check handleSignIn performs.
+	Password string `json:"password"`
+}
+
+// deletionReceipt is the response to a successful /api/gdpr/delete-all
+// call, summarizing exactly what was removed.
+type deletionReceipt struct {
+	Tasks          int `json:"tasks"`
+	SentReminders  int `json:"sent_reminders"`
+	TimeEntries    int `json:"time_entries"`
+	Notes          int `json:"notes"`
+	Links          int `json:"links"`
+	Collaborators  int `json:"collaborators"`
+	APITokens      int `json:"api_tokens"`
+	RefreshRevoked int `json:"refresh_tokens_revoked"`
+	TrashedTasks   int `json:"trashed_tasks"`
+}
+
+// handleDeleteAllData wipes every task and everything that references one,
+// along with every collaborator's credentials, then revokes every
+// outstanding session and API token - the owner's and every
+// collaborator's alike - requiring the owner's password as
+// re-confirmation since the wipe can't be undone.
+func (s *Server) handleDeleteAllData(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	if s.auth == nil {
+		s.writeError(w, r, http.StatusServiceUnavailable, errors.New("аутентификация не настроена"))
+		return
+	}
+
+	var req deleteAllDataRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+	if !s.auth.CheckPassword(req.Password) {
+		s.writeError(w, r, http.StatusUnauthorized, errors.New("неверный пароль"))
+		return
+	}
+
+	report, err := s.dataWiper.WipeAllData(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	tokens, err := s.sessions.ListAPITokens(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	for _, t := range tokens {
+		if err := s.sessions.DeleteAPIToken(r.Context(), t.ID); err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, deletionReceipt{
+		Tasks:          report.Tasks,
+		SentReminders:  report.SentReminders,
+		TimeEntries:    report.TimeEntries,
+		Notes:          report.Notes,
+		Links:          report.Links,
+		Collaborators:  report.Collaborators,
+		APITokens:      len(tokens),
+		RefreshRevoked: report.RefreshRevoked,
+		TrashedTasks:   report.TrashedTasks,
+	})
+}
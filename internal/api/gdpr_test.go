@@ -0,0 +1,73 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/api/middleware"
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+func newTestStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "scheduler.db")
+	store, err := sqlite.Open(file, sqlite.Options{})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func deleteAllDataRequestBody(t *testing.T, password string) *bytes.Buffer {
+	t.Helper()
+	body, err := json.Marshal(deleteAllDataRequest{Password: password})
+	require.NoError(t, err)
+	return bytes.NewBuffer(body)
+}
+
+func TestHandleDeleteAllDataRequiresOwner(t *testing.T) {
+	store := newTestStore(t)
+	authManager := auth.NewManager("owner-password", "")
+	server := &Server{sessions: store, dataWiper: store, auth: authManager}
+
+	protected := middleware.ValidateTokenMiddleware(authManager, store)(http.HandlerFunc(server.handleDeleteAllData))
+
+	collabToken, _, err := authManager.NewAccessToken("carol", "write")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gdpr/delete-all", deleteAllDataRequestBody(t, "owner-password"))
+	req.Header.Set("Authorization", "Bearer "+collabToken)
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+
+	// A write-permission collaborator is not the owner and must be
+	// rejected before ever reaching the password check.
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestHandleDeleteAllDataAllowsOwner(t *testing.T) {
+	store := newTestStore(t)
+	authManager := auth.NewManager("owner-password", "")
+	server := &Server{sessions: store, dataWiper: store, auth: authManager}
+
+	protected := middleware.ValidateTokenMiddleware(authManager, store)(http.HandlerFunc(server.handleDeleteAllData))
+
+	ownerToken, _, err := authManager.NewAccessToken("", "write")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/gdpr/delete-all", deleteAllDataRequestBody(t, "owner-password"))
+	req.Header.Set("Authorization", "Bearer "+ownerToken)
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
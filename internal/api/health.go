@@ -0,0 +1,30 @@
+package api
+
+import "net/http"
+
+// HealthChecker reports the outcome of the most recent background
+// health check (e.g. the periodic database integrity check), so
+// handleHealth can surface it without performing the check itself.
+type HealthChecker interface {
+	Healthy() (ok bool, message string)
+}
+
+type healthResponse struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// handleHealth reports whether the server's background checks are
+// passing. With no HealthChecker configured it always reports healthy,
+// since there's nothing to check.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{OK: true}
+	if s.healthChecker != nil {
+		resp.OK, resp.Message = s.healthChecker.Healthy()
+	}
+	status := http.StatusOK
+	if !resp.OK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
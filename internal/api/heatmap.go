@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+)
+
+const heatmapRangeDays = 365
+
+// heatmapDay is how many tasks were completed on one date, for the
+// GitHub-style activity calendar in a heatmap response.
+type heatmapDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// handleStatsHeatmap reports one entry per day with at least one
+// completion over the last year, derived from the outbox's event history
+// rather than a dedicated stats table - see handleStats.
+func (s *Server) handleStatsHeatmap(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().AddDate(0, 0, -heatmapRangeDays)
+
+	history, err := s.eventHistory.EventsSince(r.Context(), since)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	counts := map[string]int{}
+	for _, row := range history {
+		var event events.Event
+		if err := json.Unmarshal(row.Payload, &event); err != nil {
+			s.writeError(w, r, http.StatusInternalServerError,
+				fmt.Errorf("decode outbox event #%d: %w", row.ID, err))
+			return
+		}
+		if event.Type != events.TaskCompleted {
+			continue
+		}
+		counts[nextdate.Format(row.CreatedAt)]++
+	}
+
+	dates := make([]string, 0, len(counts))
+	for d := range counts {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	days := make([]heatmapDay, len(dates))
+	for i, d := range dates {
+		days[i] = heatmapDay{Date: d, Count: counts[d]}
+	}
+	writeJSON(w, http.StatusOK, map[string][]heatmapDay{"days": days})
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// csvImportResult is one row's outcome from a CSV import. Row counts data
+// rows starting at 1, matching the line a spreadsheet editor would show for
+// that row (the header is row 0 there, not counted).
+type csvImportResult struct {
+	Row   int    `json:"row"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// csvRow is one line of an import CSV, columns identified by their header
+// name rather than position.
+type csvRow struct {
+	date, title, comment, repeat string
+}
+
+// handleImportCSV bulk-adds tasks from a CSV file with a "date,title"
+// header, plus optional "comment" and "repeat" columns. Every row is
+// validated the same way a single POST /api/task would be; if any row
+// fails, or the caller passed ?dry_run=1, nothing is added and the
+// per-row report describes what would have happened. Otherwise all rows
+// are added in one transaction (see storage.Store.ImportTasks) so a bulk
+// load can't leave the list half-imported.
+func (s *Server) handleImportCSV(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "1"
+
+	rows, err := parseCSVRows(r.Body)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks := make([]storage.Task, len(rows))
+	results := make([]csvImportResult, len(rows))
+	valid := true
+	for i, row := range rows {
+		results[i].Row = i + 1
+
+		t, err := prepareTask(storage.Task{}, taskRequest{
+			Date:    row.date,
+			Title:   row.title,
+			Comment: row.comment,
+			Repeat:  row.repeat,
+		})
+		if err != nil {
+			results[i].Error = err.Error()
+			valid = false
+			continue
+		}
+		tasks[i] = t
+	}
+
+	if dryRun || !valid {
+		writeJSON(w, http.StatusOK, map[string]any{"imported": 0, "dry_run": dryRun, "results": results})
+		return
+	}
+
+	ids, err := s.store.ImportTasks(r.Context(), tasks)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	for i, id := range ids {
+		results[i].ID = id
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"imported": len(ids), "dry_run": false, "results": results})
+}
+
+// parseCSVRows reads r as a CSV file whose header names its columns,
+// requiring at least "date" and "title".
+func parseCSVRows(r io.Reader) ([]csvRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("файл не содержит данных")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV: %w", err)
+	}
+
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	dateIdx, ok := col["date"]
+	if !ok {
+		return nil, errors.New(`в заголовке CSV отсутствует колонка "date"`)
+	}
+	titleIdx, ok := col["title"]
+	if !ok {
+		return nil, errors.New(`в заголовке CSV отсутствует колонка "title"`)
+	}
+	commentIdx, hasComment := col["comment"]
+	repeatIdx, hasRepeat := col["repeat"]
+
+	var rows []csvRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать строку CSV: %w", err)
+		}
+
+		row := csvRow{date: csvField(record, dateIdx), title: csvField(record, titleIdx)}
+		if hasComment {
+			row.comment = csvField(record, commentIdx)
+		}
+		if hasRepeat {
+			row.repeat = csvField(record, repeatIdx)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("файл не содержит строк с данными")
+	}
+	return rows, nil
+}
+
+func csvField(record []string, idx int) string {
+	if idx < 0 || idx >= len(record) {
+		return ""
+	}
+	return record[idx]
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// icsImportResult is one component's outcome from an iCalendar import.
+type icsImportResult struct {
+	Summary string `json:"summary"`
+	ID      int64  `json:"id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// icsComponent is the subset of a VTODO/VEVENT's properties the importer
+// understands.
+type icsComponent struct {
+	summary     string
+	description string
+	date        string
+	rrule       string
+}
+
+// handleImportICS reads an iCalendar file from the request body and adds a
+// task for each VTODO/VEVENT it contains, translating RRULE into the
+// scheduler's repeat DSL (see nextdate.FromRRule). Unlike /api/import/csv,
+// items are added one at a time rather than in a single transaction: a
+// migrated calendar is expected to contain entries the scheduler can't
+// represent, and those should be reported and skipped rather than failing
+// the whole import.
+func (s *Server) handleImportICS(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	components, err := parseICSComponents(r.Body)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	results := make([]icsImportResult, 0, len(components))
+	imported := 0
+	for _, c := range components {
+		var repeat string
+		if c.rrule != "" {
+			var err error
+			repeat, err = nextdate.FromRRule(c.rrule)
+			if err != nil {
+				results = append(results, icsImportResult{Summary: c.summary, Error: err.Error()})
+				continue
+			}
+		}
+
+		t, err := prepareTask(storage.Task{}, taskRequest{
+			Date:    c.date,
+			Title:   c.summary,
+			Comment: c.description,
+			Repeat:  repeat,
+		})
+		if err != nil {
+			results = append(results, icsImportResult{Summary: c.summary, Error: err.Error()})
+			continue
+		}
+
+		id, err := s.store.AddTask(r.Context(), t)
+		if err != nil {
+			results = append(results, icsImportResult{Summary: c.summary, Error: err.Error()})
+			continue
+		}
+		imported++
+		results = append(results, icsImportResult{Summary: c.summary, ID: id})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"imported": imported, "results": results})
+}
+
+// parseICSComponents extracts every VTODO/VEVENT in r's iCalendar data.
+func parseICSComponents(r io.Reader) ([]icsComponent, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []icsComponent
+	var cur *icsComponent
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VTODO" || line == "BEGIN:VEVENT":
+			cur = &icsComponent{}
+		case line == "END:VTODO" || line == "END:VEVENT":
+			if cur != nil {
+				components = append(components, *cur)
+				cur = nil
+			}
+		case cur != nil:
+			name, value, ok := splitICSLine(line)
+			if !ok {
+				continue
+			}
+			switch name {
+			case "SUMMARY":
+				cur.summary = unescapeICSText(value)
+			case "DESCRIPTION":
+				cur.description = unescapeICSText(value)
+			case "RRULE":
+				cur.rrule = value
+			case "DTSTART", "DUE":
+				if date := extractICSDate(value); date != "" {
+					cur.date = date
+				}
+			}
+		}
+	}
+	if len(components) == 0 {
+		return nil, errors.New("файл не содержит компонентов VTODO или VEVENT")
+	}
+	return components, nil
+}
+
+// unfoldICSLines reads r as RFC 5545 content lines, joining a folded
+// continuation (one starting with a space or tab) onto the line before it.
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitICSLine splits a content line "NAME;PARAM=X:value" into its
+// property name and value, discarding parameters this importer has no use
+// for.
+func splitICSLine(line string) (name, value string, ok bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", "", false
+	}
+	head := line[:colon]
+	if semi := strings.Index(head, ";"); semi >= 0 {
+		head = head[:semi]
+	}
+	return strings.ToUpper(head), line[colon+1:], true
+}
+
+// extractICSDate pulls the scheduler's YYYYMMDD date out of a DTSTART/DUE
+// value, which may carry a time-of-day or "Z" suffix this importer ignores.
+func extractICSDate(value string) string {
+	if len(value) < 8 {
+		return ""
+	}
+	date := value[:8]
+	if _, err := nextdate.Parse(date); err != nil {
+		return ""
+	}
+	return date
+}
+
+// unescapeICSText reverses escapeICSText.
+func unescapeICSText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i+1])
+			}
+			i++
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
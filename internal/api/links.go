@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type addLinkRequest struct {
+	RelatedTaskID int64                `json:"related_task_id"`
+	Relation      storage.LinkRelation `json:"relation"`
+}
+
+// handleAddLink links task ?id to req.RelatedTaskID with the given
+// relation, e.g. "related" or "duplicate_of".
+func (s *Server) handleAddLink(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req addLinkRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+	if !req.Relation.Valid() {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("некорректный тип связи"))
+		return
+	}
+	if req.RelatedTaskID == id {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("задача не может быть связана сама с собой"))
+		return
+	}
+
+	if _, err := s.store.Task(r.Context(), id); errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := s.store.Task(r.Context(), req.RelatedTaskID); errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	link, err := s.linkStore.AddLink(r.Context(), id, req.RelatedTaskID, req.Relation, time.Now())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, link)
+}
+
+// handleRemoveLink removes one link by its own id, not either task's.
+func (s *Server) handleRemoveLink(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.linkStore.RemoveLink(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrLinkNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, r, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
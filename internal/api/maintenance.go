@@ -0,0 +1,210 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// handleIntegrityCheck runs PRAGMA integrity_check against the live
+// database and reports what it found.
+func (s *Server) handleIntegrityCheck(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	report, err := s.maintainer.IntegrityCheck(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleVacuum rebuilds the database file to reclaim space and
+// defragment it.
+func (s *Server) handleVacuum(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	report, err := s.maintainer.Vacuum(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleBackup writes a consistent snapshot of the database to the path
+// given by the "path" query parameter, resolved inside the configured
+// backup directory (TODO_BACKUP_DIR) - it never writes to a path the
+// caller names outright, since that would let anyone who reaches this
+// endpoint make the server overwrite an arbitrary file on its own
+// filesystem.
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	name := r.URL.Query().Get("path")
+	if name == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("не указан путь для резервной копии"))
+		return
+	}
+	path, err := s.resolveBackupPath(name)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	report, err := s.maintainer.Backup(r.Context(), path)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// resolveBackupPath resolves name against the configured backup
+// directory and confirms the result stays inside it, rejecting any
+// ".." traversal or absolute path that would escape it. An empty
+// backupDir - no TODO_BACKUP_DIR configured - rejects every request,
+// since there is then no directory the server is willing to write to.
+func (s *Server) resolveBackupPath(name string) (string, error) {
+	if s.backupDir == "" {
+		return "", errors.New("резервное копирование через API не настроено (TODO_BACKUP_DIR)")
+	}
+	dir, err := filepath.Abs(s.backupDir)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if path != dir && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", errors.New("путь для резервной копии должен находиться внутри каталога резервных копий")
+	}
+	return path, nil
+}
+
+// handleCheckpoint folds WAL frames back into the main database file.
+// mode is an optional query parameter (PASSIVE, FULL, RESTART or
+// TRUNCATE); empty defaults to PASSIVE.
+func (s *Server) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	report, err := s.maintainer.Checkpoint(r.Context(), r.URL.Query().Get("mode"))
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleReplicationStatus reports the WAL configuration a continuous
+// replication tool (e.g. Litestream) needs to poll: journal mode,
+// whether SQLite's own autocheckpointing is enabled, and how much WAL
+// is currently unflushed.
+func (s *Server) handleReplicationStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	status, err := s.maintainer.ReplicationStatus(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleRepositoryMetrics reports call count and latency for the task
+// repository's hot methods, so debugging a slow list load doesn't start
+// from guesswork.
+func (s *Server) handleRepositoryMetrics(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	stats, err := s.maintainer.RepositoryMetrics(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// CacheStats reports the cumulative hit and miss counts of whatever
+// read cache, if any, sits in front of the task store, so handleCacheStats
+// can surface them without depending on a specific cache implementation.
+type CacheStats interface {
+	Stats() (hits, misses int64)
+}
+
+type cacheStatsResponse struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// handleCacheStats reports hit/miss counts for the read cache in front
+// of the task store, if one is configured.
+func (s *Server) handleCacheStats(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	var resp cacheStatsResponse
+	if s.cacheStats != nil {
+		resp.Hits, resp.Misses = s.cacheStats.Stats()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleStreamBackup takes a consistent snapshot of the database and
+// streams it straight to the client, so an operator can pull a backup
+// without the server writing one to a path it can reach on disk.
+func (s *Server) handleStreamBackup(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+
+	tmp, err := os.CreateTemp("", "todo-backup-*.db")
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	// The temp file must not exist for VACUUM INTO to write to it.
+	if err := os.Remove(tmpPath); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := s.maintainer.Backup(r.Context(), tmpPath); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	f, err := os.Open(tmpPath)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	defer f.Close()
+
+	filename := fmt.Sprintf("backup-%s.db", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if fi, err := f.Stat(); err == nil {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", fi.Size()))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		// Headers are already sent, so there's no well-formed error left
+		// to send the client; report it the same way handleExportNDJSON
+		// does for a failure partway through a stream.
+		s.reporter.Report(err, chimiddleware.GetReqID(r.Context()))
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessLog logs method, path, status, latency, response size and request
+// ID for every request. When skipStatic is true, requests outside /api
+// (the static web UI) are excluded, since their volume drowns out the API
+// traffic operators actually care about.
+func AccessLog(skipStatic bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skipStatic && !strings.HasPrefix(r.URL.Path, "/api") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+			next.ServeHTTP(ww, r)
+
+			log.Printf("%s %s %d %dB %s [%s]", r.Method, r.URL.Path, ww.Status(), ww.BytesWritten(), time.Since(start), chimiddleware.GetReqID(r.Context()))
+		})
+	}
+}
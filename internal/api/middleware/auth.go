@@ -0,0 +1,136 @@
+// Package middleware holds cross-cutting HTTP middleware for the
+// scheduler API.
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type contextKey int
+
+const (
+	// permissionKey is the context key under which the caller's permission
+	// on the shared task list is stored by ValidateTokenMiddleware.
+	permissionKey contextKey = iota
+	// subjectKey is the context key under which the caller's identity is
+	// stored by ValidateTokenMiddleware: empty for the owner, or a
+	// collaborator's username.
+	subjectKey
+)
+
+// PermissionFrom returns the permission the current request's identity was
+// granted on the shared task list. It defaults to storage.PermissionWrite
+// when authentication is disabled or no permission was set, matching the
+// owner's unrestricted access.
+func PermissionFrom(ctx context.Context) storage.Permission {
+	if perm, ok := ctx.Value(permissionKey).(storage.Permission); ok {
+		return perm
+	}
+	return storage.PermissionWrite
+}
+
+// IsOwner reports whether the current request's identity is the instance
+// owner rather than an invited collaborator. It defaults to true when
+// authentication is disabled, an API token or mTLS client certificate was
+// used, or no subject was set, since none of those carry a collaborator
+// identity - only a collaborator access token does, via its JWT subject.
+func IsOwner(ctx context.Context) bool {
+	subject, ok := ctx.Value(subjectKey).(string)
+	return !ok || subject == ""
+}
+
+// ValidateTokenMiddleware authenticates a request against the configured
+// password. Callers apply it only to the router group that needs
+// protecting - sign-in and refresh stay outside that group, since a
+// session can't be required before one exists. The token is read from the
+// Authorization: Bearer header first, falling back to the "token" cookie
+// for browser clients, and finally to a "token" query parameter for
+// clients that can set neither, such as a calendar app subscribing to
+// /api/calendar.ics. It accepts either a JWT access token (checked
+// against the revocation list) or a personal access token (checked
+// against sessions, with its last-used timestamp updated). When manager
+// is nil, authentication is disabled and every request passes through.
+//
+// A request that already presented a client certificate the TLS layer
+// verified against the configured CA (see autotls's ClientCAs wiring) is
+// treated as an authenticated machine client with the owner's full
+// permission, bypassing the password/JWT check entirely - this is the
+// mTLS mode for machine-to-machine clients that have no password to send.
+func ValidateTokenMiddleware(manager *auth.Manager, sessions storage.SessionStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if manager == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				if cookie, err := r.Cookie("token"); err == nil {
+					token = cookie.Value
+					ok = true
+				}
+			}
+			if !ok {
+				token = r.URL.Query().Get("token")
+				ok = token != ""
+			}
+			if !ok {
+				unauthorized(w)
+				return
+			}
+
+			if auth.IsAPIToken(token) {
+				at, err := sessions.APITokenByHash(r.Context(), auth.HashToken(token))
+				if err != nil {
+					unauthorized(w)
+					return
+				}
+				_ = sessions.TouchAPIToken(r.Context(), at.ID, time.Now())
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := manager.ParseAccessToken(token)
+			if err != nil {
+				unauthorized(w)
+				return
+			}
+
+			if isRevoked, err := sessions.IsTokenRevoked(r.Context(), claims.JTI); err != nil || isRevoked {
+				unauthorized(w)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), permissionKey, storage.Permission(claims.Permission))
+			ctx = context.WithValue(ctx, subjectKey, claims.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// header, if present.
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(header, prefix), true
+}
+
+func unauthorized(w http.ResponseWriter) {
+	http.Error(w, `{"error":"Authentication required"}`, http.StatusUnauthorized)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// Compress gzip-encodes responses for clients that advertise support via
+// Accept-Encoding, so a large task list or the JS bundle goes over the wire
+// compressed instead of raw. Only gzip is offered: brotli would need
+// compress/brotli or a third-party module, neither available in this
+// build, and gzip already covers every client that matters here.
+//
+// Requests carrying a Range header are left uncompressed: the static file
+// server answers those with a byte range of the raw file, and gzipping
+// just that slice would produce a truncated stream nothing can decode.
+//
+// Event streams (Accept: text/event-stream) are also left uncompressed:
+// gzip.Writer buffers output until it has enough to flush a block, which
+// would hold back events instead of delivering them as they're published,
+// and the wrapping response writer below doesn't implement http.Flusher
+// for the handler to force it anyway. WebSocket upgrade requests are left
+// alone for the same reason the response writer isn't an http.Flusher
+// here: it isn't an http.Hijacker either, and the upgrade needs one to
+// take over the raw connection.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" ||
+			strings.Contains(r.Header.Get("Accept"), "text/event-stream") ||
+			strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+			!strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gz: gw}, r)
+	})
+}
+
+// gzipResponseWriter routes Write through the gzip writer while leaving
+// WriteHeader and Header untouched, so downstream handlers keep setting
+// status codes and headers normally.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	return w.gz.Write(p)
+}
+
+// WriteHeader drops Content-Length and Accept-Ranges: both describe the
+// uncompressed body, and a stale Content-Length would truncate the
+// response once gzip changes its size.
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	w.Header().Del("Content-Length")
+	w.Header().Del("Accept-Ranges")
+	w.ResponseWriter.WriteHeader(status)
+}
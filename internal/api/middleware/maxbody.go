@@ -0,0 +1,18 @@
+package middleware
+
+import "net/http"
+
+// MaxBodySize caps the size of request bodies at limit bytes, so an
+// oversized upload fails fast instead of being decoded in full. Handlers
+// see the limit as a read error from r.Body (an *http.MaxBytesError) and
+// should report it as 413. limit <= 0 disables the check.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type schemeKey struct{}
+
+// SchemeFrom returns the request's original scheme ("http" or "https"),
+// accounting for TLS termination at a trusted reverse proxy (see
+// TrustProxy). Defaults to "http" when nothing indicates otherwise.
+func SchemeFrom(ctx context.Context) string {
+	if v, ok := ctx.Value(schemeKey{}).(string); ok {
+		return v
+	}
+	return "http"
+}
+
+// TrustProxy resolves the real client IP and scheme from the
+// X-Forwarded-For/X-Forwarded-Proto headers, used for logging, rate
+// limiting and secure-cookie decisions further down the chain. It only
+// does so when the immediate peer's address (r.RemoteAddr) is in
+// trustedProxies - otherwise an untrusted client could spoof its way past
+// IP-based rate limiting or login lockouts just by setting these headers
+// itself. trustedProxies entries may be single IPs or CIDR ranges; an
+// empty list disables the rewrite entirely (the default, safe for
+// deployments with no reverse proxy in front).
+func TrustProxy(trustedProxies []string) func(http.Handler) http.Handler {
+	nets := parseProxyNets(trustedProxies)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(nets) == 0 || !fromTrustedProxy(r.RemoteAddr, nets) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme := "http"
+			if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+				scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+			}
+			ctx := context.WithValue(r.Context(), schemeKey{}, scheme)
+
+			if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+				if ip := realClientIP(xff, nets); ip != "" {
+					r.RemoteAddr = net.JoinHostPort(ip, "0")
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// realClientIP picks the real client address out of xff, a comma-separated
+// X-Forwarded-For header. Each hop appends the peer it saw to the end of
+// the list (nginx's $proxy_add_x_forwarded_for and RFC 7239 both work this
+// way), so the trustworthy entry is the rightmost one NOT itself inside
+// nets - everything to its right was appended by a proxy we trust, and
+// everything to its left, including the leftmost entry, was supplied by
+// the client and is attacker-controlled. Returns "" if every entry turns
+// out to be a trusted proxy, e.g. a malformed or empty chain.
+func realClientIP(xff string, nets []*net.IPNet) string {
+	parts := strings.Split(xff, ",")
+	for i := len(parts) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(parts[i])
+		if ip == "" {
+			continue
+		}
+		if !fromTrustedProxy(ip, nets) {
+			return ip
+		}
+	}
+	return ""
+}
+
+// fromTrustedProxy reports whether remoteAddr's host is contained in one
+// of nets.
+func fromTrustedProxy(remoteAddr string, nets []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxyNets converts a list of IPs and/or CIDR ranges into IPNets,
+// silently dropping anything that parses as neither.
+func parseProxyNets(proxies []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		if _, n, err := net.ParseCIDR(p); err == nil {
+			nets = append(nets, n)
+			continue
+		}
+		if ip := net.ParseIP(p); ip != nil {
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return nets
+}
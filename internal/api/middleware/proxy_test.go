@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustProxySpoofedXFF(t *testing.T) {
+	var gotRemoteAddr string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRemoteAddr = r.RemoteAddr
+	})
+	handler := TrustProxy([]string{"10.0.0.0/8"})(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	// A malicious client sets the leftmost entry itself; only the
+	// rightmost entry, appended by the trusted proxy at 10.0.0.1, can be
+	// believed.
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 203.0.113.9")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	host, _, err := net.SplitHostPort(gotRemoteAddr)
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.9", host)
+}
+
+func TestTrustProxyUntrustedPeerIgnoresXFF(t *testing.T) {
+	var gotRemoteAddr string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRemoteAddr = r.RemoteAddr
+	})
+	handler := TrustProxy([]string{"10.0.0.0/8"})(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "1.2.3.4:12345"
+	req.Header.Set("X-Forwarded-For", "9.9.9.9")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "1.2.3.4:12345", gotRemoteAddr)
+}
+
+func TestRealClientIP(t *testing.T) {
+	_, trusted, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+	nets := []*net.IPNet{trusted}
+
+	// Only the rightmost, trusted-proxy-observed hop is believable; the
+	// client-supplied leftmost entry must be ignored.
+	assert.Equal(t, "203.0.113.9", realClientIP("1.2.3.4, 203.0.113.9", nets))
+
+	// Multiple trusted hops in a chain: walk past each of them.
+	assert.Equal(t, "203.0.113.9", realClientIP("1.2.3.4, 203.0.113.9, 10.0.0.5", nets))
+
+	// Every entry is itself a trusted proxy: nothing trustworthy to report.
+	assert.Equal(t, "", realClientIP("10.0.0.4, 10.0.0.5", nets))
+}
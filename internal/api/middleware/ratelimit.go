@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/ngavrilenkov/go_final_project/internal/ratelimit"
+)
+
+// RateLimit throttles the requests it wraps using limiter, keyed by the
+// caller's bearer token or "token" cookie if present, otherwise their IP.
+// A single misbehaving client is throttled without penalizing everyone
+// else sharing the API. When limiter is nil, rate limiting is disabled.
+func RateLimit(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ok, retryAfter := limiter.Allow(rateLimitKey(r))
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				http.Error(w, `{"error":"слишком много запросов, повторите позже"}`, http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the caller for rate limiting purposes: their
+// token if authenticated, otherwise their IP.
+func rateLimitKey(r *http.Request) string {
+	if token, ok := bearerToken(r); ok {
+		return token
+	}
+	if cookie, err := r.Cookie("token"); err == nil {
+		return cookie.Value
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
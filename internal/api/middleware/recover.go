@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/ngavrilenkov/go_final_project/internal/errorreporter"
+)
+
+// Recover recovers from panics in the handlers it wraps, logging the
+// panic value and stack trace together with the request ID (see
+// chimiddleware.RequestID), forwarding the panic to reporter (which may be
+// nil), and returning a JSON 500 envelope instead of killing the
+// connection.
+func Recover(reporter *errorreporter.Reporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rvr := recover(); rvr != nil {
+					if rvr == http.ErrAbortHandler {
+						panic(rvr)
+					}
+					reqID := chimiddleware.GetReqID(r.Context())
+					log.Printf("panic [%s]: %v\n%s", reqID, rvr, debug.Stack())
+					if err, ok := rvr.(error); ok {
+						reporter.Report(err, reqID)
+					} else {
+						reporter.Report(fmt.Errorf("panic: %v", rvr), reqID)
+					}
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(`{"error":"внутренняя ошибка сервера"}`))
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
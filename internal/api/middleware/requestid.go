@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// PropagateRequestID echoes the request ID assigned by chimiddleware.RequestID
+// back to the client as an X-Request-Id response header, so it can be
+// correlated with server logs regardless of how the request completes.
+func PropagateRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := chimiddleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(chimiddleware.RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
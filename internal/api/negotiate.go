@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+)
+
+// negotiateFormat picks a response encoding from the request's Accept
+// header: "xml" for application/xml, "text" for text/plain, and "" (the
+// default, JSON) for anything else, including Accept: application/json,
+// Accept: */*, or a missing header. It's a simple substring match rather
+// than a full RFC 7231 quality-value parser - every response format this
+// API serves fits that.
+func negotiateFormat(r *http.Request) string {
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/xml"):
+		return "xml"
+	case strings.Contains(accept, "text/plain"):
+		return "text"
+	default:
+		return ""
+	}
+}
+
+// writeXML writes v as an XML document with the standard declaration,
+// the XML counterpart to writeJSON.
+func writeXML(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+// writeText writes body as a text/plain response, for shell scripts and
+// legacy integrations that would rather read lines than parse JSON.
+func writeText(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+)
+
+func (s *Server) handleNextDate(w http.ResponseWriter, r *http.Request) {
+	now, err := nextdate.Parse(r.URL.Query().Get("now"))
+	if err != nil {
+		now = time.Now()
+	}
+
+	next, err := nextdate.Next(now, r.URL.Query().Get("date"), r.URL.Query().Get("repeat"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Write([]byte(next))
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type addNoteRequest struct {
+	Body string `json:"body"`
+}
+
+// handleAddNote appends a note to task ?id's activity feed.
+func (s *Server) handleAddNote(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req addNoteRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Body == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("не указан текст заметки"))
+		return
+	}
+
+	if _, err := s.store.Task(r.Context(), id); errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	note, err := s.noteStore.AddNote(r.Context(), id, req.Body, time.Now())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, note)
+}
+
+// handleListNotes returns task ?id's activity feed, oldest first.
+func (s *Server) handleListNotes(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	notes, err := s.noteStore.Notes(r.Context(), id)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"notes": notes})
+}
+
+// handleDeleteNote removes one note from a task's activity feed by its own
+// id, not the task's.
+func (s *Server) handleDeleteNote(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.noteStore.DeleteNote(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNoteNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, r, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
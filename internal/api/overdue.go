@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// handleOverdueTasks reports every task dated strictly before today, most
+// overdue first. Unlike /api/tasks/today, every task in the response is
+// overdue by definition, so there's no need to flag it per task.
+func (s *Server) handleOverdueTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.store.TasksDueBy(r.Context(), yesterday(time.Now()))
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]storage.Task{"tasks": tasks})
+}
+
+// handleRescheduleOverdue moves every overdue task to today, or, for a
+// recurring task, to its next occurrence after today - the bulk
+// equivalent of opening each overdue task and saving it unchanged, which
+// is what the "date can't be in the past" rule in prepareTask already
+// does one task at a time.
+func (s *Server) handleRescheduleOverdue(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	now := time.Now()
+	today := nextdate.Format(now)
+
+	tasks, err := s.store.TasksDueBy(r.Context(), yesterday(now))
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, t := range tasks {
+		if t.Repeat == "" {
+			t.Date = today
+		} else {
+			anchor := t.RepeatAnchor
+			if anchor == "" {
+				anchor = t.Date
+			}
+			next, err := nextdate.Next(now, anchor, t.Repeat)
+			if err != nil {
+				s.writeError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			t.Date = next
+			t.RepeatAnchor = next
+		}
+		if err := s.store.UpdateTask(r.Context(), t); err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"rescheduled": len(tasks)})
+}
+
+// yesterday formats the day before now, the upper bound for "overdue" -
+// today's own tasks are due, not yet overdue.
+func yesterday(now time.Time) string {
+	return nextdate.Format(now.AddDate(0, 0, -1))
+}
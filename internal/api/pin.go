@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// handleTogglePin flips task id's Pinned flag - see storage.Task.Pinned
+// and Store.Tasks's pinned-first ordering.
+func (s *Server) handleTogglePin(w http.ResponseWriter, r *http.Request) {
+	s.toggleTaskFlag(w, r, func(t *storage.Task) { t.Pinned = !t.Pinned })
+}
+
+// handleToggleStar flips task id's Starred flag - see storage.Task.Starred
+// and Store.Tasks's starredOnly parameter.
+func (s *Server) handleToggleStar(w http.ResponseWriter, r *http.Request) {
+	s.toggleTaskFlag(w, r, func(t *storage.Task) { t.Starred = !t.Starred })
+}
+
+// toggleTaskFlag loads the task named by the id query parameter, applies
+// flip to it and writes it back, returning the updated task.
+func (s *Server) toggleTaskFlag(w http.ResponseWriter, r *http.Request, flip func(t *storage.Task)) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	flip(&t)
+
+	if err := s.store.UpdateTask(r.Context(), t); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
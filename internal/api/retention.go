@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// RetentionReporter reports what a retention run would delete without
+// deleting anything, so handleRetentionDryRun can surface it without
+// depending on the concrete retention worker.
+type RetentionReporter interface {
+	DryRun(ctx context.Context) (storage.RetentionReport, error)
+}
+
+// handleRetentionDryRun reports how many rows each configured retention
+// policy would remove right now, without deleting anything.
+func (s *Server) handleRetentionDryRun(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOwner(w, r) {
+		return
+	}
+	var report storage.RetentionReport
+	if s.retentionReporter != nil {
+		var err error
+		report, err = s.retentionReporter.DryRun(r.Context())
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,386 @@
+// Package api implements the scheduler's HTTP handlers and routing.
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/ngavrilenkov/go_final_project/internal/api/middleware"
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+	"github.com/ngavrilenkov/go_final_project/internal/errorreporter"
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/ratelimit"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+	"github.com/ngavrilenkov/go_final_project/web"
+)
+
+// tasksLimit caps how many rows the tasks listing returns in one response.
+const tasksLimit = 50
+
+// Server wires the HTTP handlers to a task store, the session store, the
+// auth manager and the static web UI.
+type Server struct {
+	store              storage.Store
+	sessions           storage.SessionStore
+	maintainer         storage.Maintainer
+	reminders          storage.ReminderStore
+	slackSigningSecret string
+	auth               *auth.Manager
+	loginLimiter       *auth.LoginLimiter
+	rateLimiter        *ratelimit.Limiter
+	cors               middleware.CORSConfig
+	maxBodyBytes       int64
+	reqTimeout         time.Duration
+	tasksDefaultLimit  int
+	tasksMaxLimit      int
+	skipStaticLog      bool
+	reporter           *errorreporter.Reporter
+	events             *events.Bus
+	webFS              fs.FS
+	staticMaxAge       int
+	basePath           string
+	trustedProxies     []string
+	allowedIPs         []string
+	deniedIPs          []string
+	router             chi.Router
+	healthChecker      HealthChecker
+	cacheStats         CacheStats
+	eventHistory       storage.EventHistory
+	timeTracker        storage.TimeTracker
+	noteStore          storage.NoteStore
+	linkStore          storage.LinkStore
+	dataWiper          storage.DataWiper
+	retentionReporter  RetentionReporter
+	backupDir          string
+}
+
+// New builds a Server ready to be used as an http.Handler. authManager may
+// be nil, in which case authentication is disabled. rateLimiter may be
+// nil, in which case rate limiting is disabled. cors.AllowedOrigins may be
+// empty, in which case CORS is disabled. maxBodyBytes <= 0 disables the
+// request body size limit. reqTimeout <= 0 disables the request timeout.
+// tasksDefaultLimit is how many rows GET /api/tasks returns when the
+// request has no ?limit= of its own; tasksMaxLimit caps ?limit= itself,
+// clamping down rather than rejecting a request that asks for more.
+// skipStaticLog excludes the static web UI from access logging. reporter
+// may be nil, in which case 5xx errors and panics are only logged. webDir
+// serves the web UI from that directory on disk instead of the copy
+// embedded in the binary; empty uses the embedded copy. staticMaxAge sets
+// how long, in seconds, browsers may cache the web UI's static assets;
+// <= 0 disables caching headers on them. basePath mounts the API and web
+// UI under that path prefix (e.g. "/todo") instead of at the root; empty
+// mounts at the root. trustedProxies lists IPs/CIDRs allowed to set
+// X-Forwarded-For/X-Forwarded-Proto; empty ignores those headers.
+// deniedIPs always blocks a matching client, and a non-empty allowedIPs
+// rejects anything that doesn't match; both empty imposes no restriction.
+// healthChecker backs the public health endpoint; nil reports healthy
+// unconditionally, so it's safe to omit when there's nothing to check.
+// maintainer backs the /admin/db maintenance endpoints. reminders backs
+// the /admin/db/archive endpoints' reminder delivery history alongside
+// store's tasks. slackSigningSecret enables /slack/commands; empty keeps
+// it disabled. eventBus, if non-nil, backs GET /api/events; a nil
+// eventBus makes that endpoint a no-op stream that never emits anything.
+// cacheStats backs /admin/db/cache-stats; nil omits hit/miss counts from
+// that response, since there's no cache in front of store to report on.
+// eventHistory backs GET /api/stats, reading the outbox's full event
+// history rather than just what's pending delivery. timeTracker backs
+// POST /api/task/timer/start|stop, the tracked-time field on GET
+// /api/task and GET /api/reports/time. noteStore backs the
+// /api/task/notes activity feed. linkStore backs the /api/task/links
+// endpoints and the linked-tasks field on GET /api/task. dataWiper backs
+// POST /api/gdpr/delete-all, the "delete all my data" endpoint.
+// retentionReporter backs GET /admin/db/retention, a dry-run report of
+// what the retention job would purge; may be nil, in which case that
+// endpoint always reports zero counts. backupDir confines the path
+// POST /admin/db/backup writes to: empty rejects every backup request,
+// since without a configured directory there is nowhere safe to write
+// an operator-supplied path to.
+func New(store storage.Store, sessions storage.SessionStore, maintainer storage.Maintainer, reminders storage.ReminderStore, slackSigningSecret string, authManager *auth.Manager, rateLimiter *ratelimit.Limiter, cors middleware.CORSConfig, maxBodyBytes int64, reqTimeout time.Duration, tasksDefaultLimit, tasksMaxLimit int, skipStaticLog bool, reporter *errorreporter.Reporter, webDir string, staticMaxAge int, basePath string, trustedProxies, allowedIPs, deniedIPs []string, eventBus *events.Bus, healthChecker HealthChecker, cacheStats CacheStats, eventHistory storage.EventHistory, timeTracker storage.TimeTracker, noteStore storage.NoteStore, linkStore storage.LinkStore, dataWiper storage.DataWiper, retentionReporter RetentionReporter, backupDir string) *Server {
+	var webFS fs.FS = web.FS
+	if webDir != "" {
+		webFS = os.DirFS(webDir)
+	}
+	s := &Server{
+		store:              store,
+		sessions:           sessions,
+		maintainer:         maintainer,
+		reminders:          reminders,
+		slackSigningSecret: slackSigningSecret,
+		auth:               authManager,
+		loginLimiter:       auth.NewLoginLimiter(),
+		rateLimiter:        rateLimiter,
+		cors:               cors,
+		maxBodyBytes:       maxBodyBytes,
+		reqTimeout:         reqTimeout,
+		tasksDefaultLimit:  tasksDefaultLimit,
+		tasksMaxLimit:      tasksMaxLimit,
+		skipStaticLog:      skipStaticLog,
+		reporter:           reporter,
+		events:             eventBus,
+		webFS:              webFS,
+		staticMaxAge:       staticMaxAge,
+		basePath:           basePath,
+		trustedProxies:     trustedProxies,
+		allowedIPs:         allowedIPs,
+		deniedIPs:          deniedIPs,
+		healthChecker:      healthChecker,
+		cacheStats:         cacheStats,
+		eventHistory:       eventHistory,
+		timeTracker:        timeTracker,
+		noteStore:          noteStore,
+		linkStore:          linkStore,
+		dataWiper:          dataWiper,
+		retentionReporter:  retentionReporter,
+		backupDir:          backupDir,
+	}
+	s.routes()
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.PropagateRequestID)
+	r.Use(middleware.TrustProxy(s.trustedProxies))
+	r.Use(middleware.IPFilter(s.allowedIPs, s.deniedIPs))
+	r.Use(middleware.AccessLog(s.skipStaticLog))
+	r.Use(middleware.Recover(s.reporter))
+	r.Use(middleware.Compress)
+
+	// The handlers are versioned at /api/v1 so a future breaking v2 (a
+	// pagination envelope, RESTful paths) can be mounted alongside it
+	// without touching v1's routes. /api is mounted as an alias of the
+	// same router so the existing web UI, which still calls /api/...,
+	// keeps working unversioned.
+	apiHandler := s.apiRouter()
+	r.Mount(s.basePath+"/api/v1", apiHandler)
+	r.Mount(s.basePath+"/api", apiHandler)
+
+	eventsHandler := s.eventsRouter()
+	r.Mount(s.basePath+"/api/v1/events", eventsHandler)
+	r.Mount(s.basePath+"/api/events", eventsHandler)
+
+	wsHandler := s.wsRouter()
+	r.Mount(s.basePath+"/api/v1/ws", wsHandler)
+	r.Mount(s.basePath+"/api/ws", wsHandler)
+
+	// Slack signs its own requests instead of presenting one of our
+	// sessions or tokens, so this lives outside the /api group's
+	// ValidateTokenMiddleware entirely.
+	r.Route(s.basePath+"/slack", func(r chi.Router) {
+		r.Use(middleware.MaxBodySize(s.maxBodyBytes))
+		r.Post("/commands", s.handleSlackCommand)
+	})
+
+	static := middleware.StaticCache(s.webFS, s.staticMaxAge)(http.FileServer(http.FS(s.webFS)))
+	if s.basePath != "" {
+		// The asset links in index.html/login.html are root-relative
+		// (e.g. href="/css/theme.css"), so under a subpath they need the
+		// prefix rewritten in; everything else (css, js, favicon) is
+		// unwrapped back to a root-relative path and handed to the same
+		// file server used at the top level.
+		r.Get(s.basePath, func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, s.basePath+"/", http.StatusMovedPermanently)
+		})
+		r.Get(s.basePath+"/", s.serveHTML("index.html"))
+		r.Get(s.basePath+"/login.html", s.serveHTML("login.html"))
+		static = http.StripPrefix(s.basePath, static)
+	}
+	r.Handle(s.basePath+"/*", static)
+	s.router = r
+}
+
+// apiRouter builds the task-management API's routes, mounted under both
+// /api/v1 and /api (see routes) so it's reachable at either path.
+func (s *Server) apiRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.CORS(s.cors))
+		r.Use(middleware.RateLimit(s.rateLimiter))
+		r.Use(middleware.MaxBodySize(s.maxBodyBytes))
+		if s.reqTimeout > 0 {
+			r.Use(chimiddleware.Timeout(s.reqTimeout))
+		}
+
+		// Public: no session exists yet, so these can't require one.
+		r.Post("/signin", s.handleSignIn)
+		r.Post("/refresh", s.handleRefresh)
+		r.Get("/version", s.handleVersion)
+		r.Get("/health", s.handleHealth)
+
+		// Protected: everything else needs a valid session or token.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ValidateTokenMiddleware(s.auth, s.sessions))
+
+			r.Post("/signout", s.handleSignOut)
+			r.Route("/tokens", func(r chi.Router) {
+				r.Post("/", s.handleCreateAPIToken)
+				r.Get("/", s.handleListAPITokens)
+				r.Delete("/{id}", s.handleDeleteAPIToken)
+			})
+			r.Route("/admin/db", func(r chi.Router) {
+				r.Post("/integrity-check", s.handleIntegrityCheck)
+				r.Post("/vacuum", s.handleVacuum)
+				r.Post("/backup", s.handleBackup)
+				r.Get("/backup", s.handleStreamBackup)
+				r.Post("/checkpoint", s.handleCheckpoint)
+				r.Get("/replication", s.handleReplicationStatus)
+				r.Get("/metrics", s.handleRepositoryMetrics)
+				r.Get("/cache-stats", s.handleCacheStats)
+				r.Get("/retention", s.handleRetentionDryRun)
+				r.Get("/archive", s.handleExportArchive)
+				r.Post("/archive", s.handleRestoreArchive)
+			})
+			r.Route("/gdpr", func(r chi.Router) {
+				r.Post("/delete-all", s.handleDeleteAllData)
+			})
+			r.Route("/collaborators", func(r chi.Router) {
+				r.Post("/", s.handleCreateCollaborator)
+				r.Get("/", s.handleListCollaborators)
+				r.Delete("/{id}", s.handleDeleteCollaborator)
+			})
+			r.Get("/nextdate", s.handleNextDate)
+			r.Get("/calendar.ics", s.handleCalendar)
+			r.Post("/import/ics", s.handleImportICS)
+			r.Post("/import/csv", s.handleImportCSV)
+			r.Get("/export/ndjson", s.handleExportNDJSON)
+			r.Route("/task", func(r chi.Router) {
+				r.Post("/", s.handleAddTask)
+				r.Get("/", s.handleGetTask)
+				r.Put("/", s.handleUpdateTask)
+				r.Delete("/", s.handleDeleteTask)
+				r.Post("/done", s.handleDoneTask)
+				r.Post("/snooze", s.handleSnoozeTask)
+				r.Post("/skip", s.handleSkipTask)
+				r.Post("/timer/start", s.handleStartTimer)
+				r.Post("/timer/stop", s.handleStopTimer)
+				r.Post("/pin", s.handleTogglePin)
+				r.Post("/star", s.handleToggleStar)
+				r.Route("/notes", func(r chi.Router) {
+					r.Post("/", s.handleAddNote)
+					r.Get("/", s.handleListNotes)
+					r.Delete("/{id}", s.handleDeleteNote)
+				})
+				r.Route("/links", func(r chi.Router) {
+					r.Post("/", s.handleAddLink)
+					r.Delete("/{id}", s.handleRemoveLink)
+				})
+			})
+			r.Get("/tasks", s.handleGetTasks)
+			r.Get("/tasks/today", s.handleTodayTasks)
+			r.Get("/tasks/upcoming", s.handleUpcomingTasks)
+			r.Get("/tasks/overdue", s.handleOverdueTasks)
+			r.Get("/tasks/can-start", s.handleCanStartTasks)
+			r.Post("/tasks/overdue/reschedule", s.handleRescheduleOverdue)
+			r.Post("/tasks/reschedule", s.handleBulkReschedule)
+			r.Get("/stats", s.handleStats)
+			r.Get("/stats/heatmap", s.handleStatsHeatmap)
+			r.Get("/tasks/streak", s.handleTaskStreak)
+			r.Get("/summary", s.handleSummary)
+			r.Get("/reports/time", s.handleTimeReport)
+			r.Get("/reports/estimates", s.handleEstimatesReport)
+		})
+	})
+	return r
+}
+
+// eventsRouter builds the /api/events subrouter, mounted under both
+// /api/v1/events and /api/events (see routes). It's kept separate from
+// apiRouter rather than nested inside it: it needs the same auth as the
+// rest of the API, but must not inherit chimiddleware.Timeout, which
+// would cut the connection after reqTimeout instead of leaving it open
+// for the client's session.
+func (s *Server) eventsRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.CORS(s.cors))
+	r.Use(middleware.RateLimit(s.rateLimiter))
+	r.Use(middleware.ValidateTokenMiddleware(s.auth, s.sessions))
+	r.Get("/", s.handleEvents)
+	return r
+}
+
+// wsRouter builds the /api/ws subrouter, mounted under both /api/v1/ws
+// and /api/ws (see routes). Same reasoning as eventsRouter: kept outside
+// chimiddleware.Timeout so an open connection isn't cut after reqTimeout.
+func (s *Server) wsRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.CORS(s.cors))
+	r.Use(middleware.RateLimit(s.rateLimiter))
+	r.Use(middleware.ValidateTokenMiddleware(s.auth, s.sessions))
+	r.Get("/", s.handleWS)
+	return r
+}
+
+// serveHTML serves name from webFS with its root-relative asset links
+// rewritten to include basePath, so the page loads its CSS/JS from the
+// same subpath it was served under.
+func (s *Server) serveHTML(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := fs.ReadFile(s.webFS, name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(rewriteAssetLinks(data, s.basePath))
+	}
+}
+
+// rewriteAssetLinks prefixes basePath onto this app's root-relative asset
+// references (href/src="/css|/js|/favicon.ico...") in an HTML page.
+func rewriteAssetLinks(data []byte, basePath string) []byte {
+	for _, root := range []string{"/css", "/js", "/favicon.ico"} {
+		data = bytes.ReplaceAll(data, []byte(`="`+root), []byte(`="`+basePath+root))
+	}
+	return data
+}
+
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error envelope and, for 5xx responses, forwards
+// err to the configured error reporter.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error(), RequestID: chimiddleware.GetReqID(r.Context())})
+	if status >= http.StatusInternalServerError {
+		s.reporter.Report(err, chimiddleware.GetReqID(r.Context()))
+	}
+}
+
+// decodeJSON decodes the request body into v, writing a JSON error
+// response and reporting false on failure. An oversized body (see
+// middleware.MaxBodySize) is reported as 413, any other decode failure as
+// 400.
+func (s *Server) decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.writeError(w, r, http.StatusRequestEntityTooLarge, errors.New("тело запроса слишком большое"))
+			return false
+		}
+		s.writeError(w, r, http.StatusBadRequest, errors.New("некорректный JSON"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// handleSkipTask advances a recurring task to its next occurrence without
+// recording a completion - use this for an occurrence that won't happen,
+// as opposed to POST /api/task/done for one that was. It's rejected for
+// non-repeating tasks, which have no next occurrence to advance to.
+func (s *Server) handleSkipTask(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if t.Repeat == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("пропуск доступен только для повторяющихся задач"))
+		return
+	}
+
+	anchor := t.RepeatAnchor
+	if anchor == "" {
+		anchor = t.Date
+	}
+	next, err := nextdate.Next(time.Now(), anchor, t.Repeat)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.store.SkipTask(r.Context(), t, next); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
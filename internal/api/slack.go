@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/slack"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// handleSlackCommand serves Slack's /todo slash command: "list" (or empty
+// text) lists tasks, anything else adds a task due today titled with the
+// given text. Every request must carry a valid Slack request signature,
+// verified against slackSigningSecret.
+func (s *Server) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	if s.slackSigningSecret == "" {
+		s.writeError(w, r, http.StatusServiceUnavailable, errors.New("интеграция со Slack не настроена"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("не удалось прочитать тело запроса"))
+		return
+	}
+	if !slack.VerifySignature(s.slackSigningSecret, r.Header.Get("X-Slack-Request-Timestamp"), body, r.Header.Get("X-Slack-Signature"), time.Now()) {
+		s.writeError(w, r, http.StatusUnauthorized, errors.New("недействительная подпись Slack"))
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("некорректное тело запроса"))
+		return
+	}
+	cmd := slack.ParseCommand(form)
+
+	text, err := s.runSlackCommand(r, cmd)
+	if err != nil {
+		writeJSON(w, http.StatusOK, slackResponse{ResponseType: "ephemeral", Text: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, slackResponse{ResponseType: "ephemeral", Text: text})
+}
+
+// runSlackCommand executes cmd's text as either "list" (the default, with
+// no text) or "add <title>", returning the message to show the Slack user.
+func (s *Server) runSlackCommand(r *http.Request, cmd slack.Command) (string, error) {
+	text := strings.TrimSpace(cmd.Text)
+
+	if text == "" || text == "list" {
+		tasks, err := s.store.Tasks(r.Context(), "", tasksLimit, false, "", nil, "")
+		if err != nil {
+			return "", err
+		}
+		return slack.FormatSummary(tasks), nil
+	}
+
+	title, ok := strings.CutPrefix(text, "add ")
+	if !ok {
+		return "неизвестная команда: используйте \"/todo list\" или \"/todo add <заголовок>\"", nil
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "не указан заголовок задачи", nil
+	}
+
+	t, err := prepareTask(storage.Task{}, taskRequest{Title: title})
+	if err != nil {
+		return err.Error(), nil
+	}
+	id, err := s.store.AddTask(r.Context(), t)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("задача #%d добавлена: %s", id, title), nil
+}
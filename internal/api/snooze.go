@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// handleSnoozeTask moves a task's Date to until without touching its
+// RepeatAnchor, so a recurring task's schedule keeps its original phase
+// once the snoozed occurrence is eventually completed. This is the
+// endpoint to use instead of editing a recurring task's date directly
+// through PUT /api/task, which re-anchors the schedule on the edited
+// date - see prepareTask.
+func (s *Server) handleSnoozeTask(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	until, err := parseSnoozeUntil(r.URL.Query().Get("until"), t.Date)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if until <= t.Date {
+		s.writeError(w, r, http.StatusBadRequest,
+			fmt.Errorf("until должен быть позже текущей даты задачи %s", t.Date))
+		return
+	}
+
+	t.Date = until
+	if err := s.store.UpdateTask(r.Context(), t); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// parseSnoozeUntil parses the until query param, either a canonical
+// YYYYMMDD date or a "+Nd" shorthand for N days after from.
+func parseSnoozeUntil(raw, from string) (string, error) {
+	if raw == "" {
+		return "", errors.New("не указана дата until")
+	}
+	if strings.HasPrefix(raw, "+") && strings.HasSuffix(raw, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(raw, "+"), "d"))
+		if err != nil || days < 1 {
+			return "", fmt.Errorf("until в формате +Nd должен задавать положительное число дней")
+		}
+		fromDate, err := nextdate.Parse(from)
+		if err != nil {
+			return "", fmt.Errorf("дата задачи представлена в некорректном формате: %w", err)
+		}
+		return nextdate.Format(fromDate.AddDate(0, 0, days)), nil
+	}
+
+	parsed, err := nextdate.Parse(raw)
+	if err != nil {
+		return "", errors.New("until представлен в некорректном формате, ожидается YYYYMMDD или +Nd")
+	}
+	return nextdate.Format(parsed), nil
+}
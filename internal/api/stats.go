@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+)
+
+const (
+	defaultStatsRangeDays = 30
+	maxStatsRangeDays     = 365
+)
+
+// dailyTaskCounts is how many tasks were created and completed on one
+// date, for the day-by-day breakdown in a stats response.
+type dailyTaskCounts struct {
+	Date      string `json:"date"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+// weekdayCount is how many tasks were completed on one weekday, summed
+// across every week in the requested range.
+type weekdayCount struct {
+	Weekday string `json:"weekday"`
+	Count   int    `json:"count"`
+}
+
+type statsResponse struct {
+	Days []dailyTaskCounts `json:"days"`
+	// AvgCompletionDelayHours is the average time between a task's
+	// creation and completion, in hours, across tasks whose creation and
+	// completion both fall within the requested range. A task completed
+	// in range but created before it is excluded, since its creation
+	// time isn't available without querying outside the range.
+	AvgCompletionDelayHours float64        `json:"avg_completion_delay_hours"`
+	BusiestWeekdays         []weekdayCount `json:"busiest_weekdays"`
+}
+
+// handleStats reports daily created/completed task counts, average
+// completion delay and the busiest weekdays for completions, all derived
+// from the outbox's event history rather than a dedicated stats table.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	days, err := parseStatsRange(r.URL.Query().Get("range"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+
+	history, err := s.eventHistory.EventsSince(r.Context(), since)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	byDate := map[string]*dailyTaskCounts{}
+	dayOf := func(date string) *dailyTaskCounts {
+		d, ok := byDate[date]
+		if !ok {
+			d = &dailyTaskCounts{Date: date}
+			byDate[date] = d
+		}
+		return d
+	}
+
+	createdAt := map[int64]time.Time{}
+	var delaySum time.Duration
+	var delayCount int
+	weekdayCounts := map[time.Weekday]int{}
+
+	for _, row := range history {
+		var event events.Event
+		if err := json.Unmarshal(row.Payload, &event); err != nil {
+			s.writeError(w, r, http.StatusInternalServerError,
+				fmt.Errorf("decode outbox event #%d: %w", row.ID, err))
+			return
+		}
+
+		switch event.Type {
+		case events.TaskCreated:
+			dayOf(nextdate.Format(row.CreatedAt)).Created++
+			createdAt[event.Task.ID] = row.CreatedAt
+		case events.TaskCompleted:
+			dayOf(nextdate.Format(row.CreatedAt)).Completed++
+			weekdayCounts[row.CreatedAt.Weekday()]++
+			if created, ok := createdAt[event.Task.ID]; ok {
+				delaySum += row.CreatedAt.Sub(created)
+				delayCount++
+			}
+		}
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	result := statsResponse{Days: make([]dailyTaskCounts, len(dates))}
+	for i, d := range dates {
+		result.Days[i] = *byDate[d]
+	}
+	if delayCount > 0 {
+		result.AvgCompletionDelayHours = delaySum.Hours() / float64(delayCount)
+	}
+	for _, wd := range orderedWeekdays {
+		if count := weekdayCounts[wd]; count > 0 {
+			result.BusiestWeekdays = append(result.BusiestWeekdays, weekdayCount{Weekday: wd.String(), Count: count})
+		}
+	}
+	sort.SliceStable(result.BusiestWeekdays, func(i, j int) bool {
+		return result.BusiestWeekdays[i].Count > result.BusiestWeekdays[j].Count
+	})
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// orderedWeekdays lists weekdays Monday-first, matching the rest of the
+// scheduler's week numbering (see nextdate.parseWeekdays), before
+// BusiestWeekdays is re-sorted busiest-first.
+var orderedWeekdays = [...]time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// parseStatsRange parses the range query param, e.g. "90d", defaulting to
+// defaultStatsRangeDays when empty.
+func parseStatsRange(raw string) (int, error) {
+	if raw == "" {
+		return defaultStatsRangeDays, nil
+	}
+	if !strings.HasSuffix(raw, "d") {
+		return 0, fmt.Errorf("range должен быть в формате <число>d, например 90d")
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+	if err != nil || days < 1 || days > maxStatsRangeDays {
+		return 0, fmt.Errorf("range должен быть числом дней от 1 до %d", maxStatsRangeDays)
+	}
+	return days, nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+type streakResponse struct {
+	IsHabit bool `json:"is_habit"`
+	// CurrentStreak is how many times in a row the task was most
+	// recently completed on or before its due date.
+	CurrentStreak int `json:"current_streak"`
+	// BestStreak is the longest such run in the task's history,
+	// including the current one if it's the longest.
+	BestStreak int `json:"best_streak"`
+}
+
+// handleTaskStreak reports id's current and best streak of on-time
+// completions, derived from the outbox's full event history rather than
+// a dedicated table - see computeStreak. It works for any task, not just
+// ones flagged is_habit; the flag only tells a client whether to surface
+// this in its UI.
+func (s *Server) handleTaskStreak(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	history, err := s.eventHistory.EventsSince(r.Context(), time.Time{})
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	current, best, err := computeStreak(history, id)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, streakResponse{
+		IsHabit:       t.IsHabit,
+		CurrentStreak: current,
+		BestStreak:    best,
+	})
+}
+
+// computeStreak walks id's TaskCompleted events oldest-first, treating a
+// completion as on-time when it lands on or before the due date recorded
+// on the event (the task's date at the moment it was completed, before
+// any reschedule - see CompleteTask). current is the trailing run of
+// on-time completions; best is the longest run anywhere in the history.
+func computeStreak(history []storage.OutboxEvent, id int64) (current, best int, err error) {
+	var run int
+	for _, row := range history {
+		var event events.Event
+		if err := json.Unmarshal(row.Payload, &event); err != nil {
+			return 0, 0, fmt.Errorf("decode outbox event #%d: %w", row.ID, err)
+		}
+		if event.Type != events.TaskCompleted || event.Task.ID != id {
+			continue
+		}
+		if nextdate.Format(row.CreatedAt) <= event.Task.Date {
+			run++
+		} else {
+			run = 0
+		}
+		if run > best {
+			best = run
+		}
+	}
+	return run, best, nil
+}
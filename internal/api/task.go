@@ -0,0 +1,358 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/api/middleware"
+	"github.com/ngavrilenkov/go_final_project/internal/markdown"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// errReadOnly is returned when a read-only collaborator attempts to
+// mutate the shared task list.
+var errReadOnly = errors.New("доступ только для чтения")
+
+// errOwnerOnly is returned when a collaborator - even one with write
+// access to the shared task list - attempts an owner-only action:
+// managing who else has access, or the /admin/db instance-maintenance
+// endpoints.
+var errOwnerOnly = errors.New("доступно только владельцу")
+
+// requireWrite reports whether the current request's identity may modify
+// the shared task list, writing a 403 response if not.
+func (s *Server) requireWrite(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.PermissionFrom(r.Context()) != storage.PermissionWrite {
+		s.writeError(w, r, http.StatusForbidden, errReadOnly)
+		return false
+	}
+	return true
+}
+
+// requireOwner reports whether the current request's identity is the
+// instance owner, writing a 403 response if not. Unlike requireWrite,
+// write access to the shared task list is not enough: a write
+// collaborator can edit tasks but must not be able to manage other
+// collaborators or reach the instance-maintenance endpoints.
+func (s *Server) requireOwner(w http.ResponseWriter, r *http.Request) bool {
+	if !middleware.IsOwner(r.Context()) {
+		s.writeError(w, r, http.StatusForbidden, errOwnerOnly)
+		return false
+	}
+	return true
+}
+
+type taskRequest struct {
+	ID      string `json:"id,omitempty"`
+	Date    string `json:"date"`
+	Title   string `json:"title"`
+	Comment string `json:"comment"`
+	Repeat  string `json:"repeat"`
+
+	// ReminderLeadDays overrides the reminder scheduler's default lead
+	// time for this task. 0 (the default) keeps the scheduler's default.
+	ReminderLeadDays int `json:"reminder_lead_days,omitempty"`
+
+	// IsHabit marks the task for streak tracking (see api.handleTaskStreak).
+	IsHabit bool `json:"is_habit,omitempty"`
+
+	// Time is the task's due time of day, "15:04" form. Empty means the
+	// task has no particular time, only a date.
+	Time string `json:"time,omitempty"`
+
+	// DurationMinutes is how long the task is expected to take.
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+
+	// StartDate is when the task can be picked up, kept separate from
+	// Date - the due date. Empty means no start constraint.
+	StartDate string `json:"start_date,omitempty"`
+
+	// Color labels the task for a UI that wants to distinguish
+	// categories at a glance - see storage.TaskColor. Empty means no
+	// color.
+	Color storage.TaskColor `json:"color,omitempty"`
+}
+
+func (s *Server) handleAddTask(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	var req taskRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+
+	t, err := prepareTask(storage.Task{}, req)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := s.store.AddTask(r.Context(), t)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": id})
+}
+
+func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	tracked, err := s.trackedMinutes(r, id)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	links, err := s.linkStore.LinksForTask(r.Context(), id)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	resp := taskWithTrackedTime{
+		Task:            t,
+		TrackedMinutes:  tracked,
+		RenderedComment: markdown.Render(t.Comment),
+		Links:           links,
+	}
+	switch negotiateFormat(r) {
+	case "xml":
+		writeXML(w, http.StatusOK, resp)
+	case "text":
+		writeText(w, http.StatusOK, taskText(resp))
+	default:
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// taskText renders t as "field: value" lines, for shell scripts and
+// legacy integrations that would rather read lines than parse JSON.
+func taskText(t taskWithTrackedTime) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "id: %d\n", t.ID)
+	fmt.Fprintf(&b, "date: %s\n", t.Date)
+	fmt.Fprintf(&b, "title: %s\n", t.Title)
+	fmt.Fprintf(&b, "comment: %s\n", t.Comment)
+	fmt.Fprintf(&b, "repeat: %s\n", t.Repeat)
+	fmt.Fprintf(&b, "tracked_minutes: %d\n", t.TrackedMinutes)
+	return b.String()
+}
+
+func (s *Server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	var req taskRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+
+	id, err := parseID(req.ID)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	existing, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	t, err := prepareTask(existing, req)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	t.ID = id
+
+	if err := s.store.UpdateTask(r.Context(), t); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, r, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.store.DeleteTask(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, r, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) handleDoneTask(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := s.store.Task(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	var next string
+	if t.Repeat != "" {
+		anchor := t.RepeatAnchor
+		if anchor == "" {
+			anchor = t.Date
+		}
+		next, err = nextdate.Next(time.Now(), anchor, t.Repeat)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err := s.store.CompleteTask(r.Context(), t, next); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// prepareTask validates req and returns existing with the requested fields
+// applied, resolving the scheduler's "date can't be in the past" rule.
+func prepareTask(existing storage.Task, req taskRequest) (storage.Task, error) {
+	if req.Title == "" {
+		return storage.Task{}, errors.New("не указан заголовок задачи")
+	}
+	if req.ReminderLeadDays < 0 {
+		return storage.Task{}, errors.New("количество дней напоминания не может быть отрицательным")
+	}
+	if req.Time != "" {
+		if _, err := time.Parse("15:04", req.Time); err != nil {
+			return storage.Task{}, errors.New("время представлено в некорректном формате, ожидается ЧЧ:ММ")
+		}
+	}
+	if req.DurationMinutes < 0 {
+		return storage.Task{}, errors.New("продолжительность не может быть отрицательной")
+	}
+	if !req.Color.Valid() {
+		return storage.Task{}, errors.New("некорректный цвет")
+	}
+
+	now := time.Now()
+	today := nextdate.Format(now)
+
+	date := req.Date
+	if date == "" {
+		date = today
+	}
+	parsed, err := nextdate.Parse(date)
+	if err != nil {
+		return storage.Task{}, errors.New("дата представлена в некорректном формате")
+	}
+
+	if req.Repeat != "" {
+		if _, err := nextdate.Next(now, date, req.Repeat); err != nil {
+			return storage.Task{}, fmt.Errorf("некорректное правило повторения: %w", err)
+		}
+	}
+
+	if isBeforeDay(parsed, now) {
+		if req.Repeat == "" {
+			date = today
+		} else if date, err = nextdate.Next(now, date, req.Repeat); err != nil {
+			return storage.Task{}, fmt.Errorf("некорректное правило повторения: %w", err)
+		}
+	}
+
+	if req.StartDate != "" {
+		if _, err := nextdate.Parse(req.StartDate); err != nil {
+			return storage.Task{}, errors.New("дата начала представлена в некорректном формате")
+		}
+		if req.StartDate > date {
+			return storage.Task{}, errors.New("дата начала не может быть позже срока задачи")
+		}
+	}
+
+	existing.Date = date
+	existing.Title = req.Title
+	existing.Comment = req.Comment
+	existing.Repeat = req.Repeat
+	existing.ReminderLeadDays = req.ReminderLeadDays
+	existing.IsHabit = req.IsHabit
+	existing.Time = req.Time
+	existing.DurationMinutes = req.DurationMinutes
+	existing.StartDate = req.StartDate
+	existing.Color = req.Color
+	if req.Repeat != "" {
+		existing.RepeatAnchor = date
+	} else {
+		existing.RepeatAnchor = ""
+	}
+	return existing, nil
+}
+
+func isBeforeDay(date, now time.Time) bool {
+	y1, m1, d1 := date.Date()
+	y2, m2, d2 := now.Date()
+	return time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC).
+		Before(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC))
+}
+
+func parseID(raw string) (int64, error) {
+	if raw == "" {
+		return 0, errors.New("не указан идентификатор задачи")
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.New("некорректный идентификатор задачи")
+	}
+	return id, nil
+}
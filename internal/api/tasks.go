@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// taskGroup is one bucket of a grouped /api/tasks response - a group key
+// (e.g. a date) plus the tasks filed under it and their count, so clients
+// don't have to derive the count themselves.
+type taskGroup struct {
+	Key   string         `json:"key" xml:"key"`
+	Count int            `json:"count" xml:"count"`
+	Tasks []storage.Task `json:"tasks" xml:"tasks>task"`
+}
+
+// tasksResponse is the /api/tasks envelope. Tasks or Groups is populated
+// depending on group_by; the counts are always present and come from
+// storage.Store.CountTasks rather than from scanning Tasks/Groups, so
+// they reflect every matching task, not just the current page.
+type tasksResponse struct {
+	XMLName  xml.Name       `json:"-" xml:"tasks"`
+	Tasks    []storage.Task `json:"tasks,omitempty" xml:"task,omitempty"`
+	Groups   []taskGroup    `json:"groups,omitempty" xml:"groups>group,omitempty"`
+	Total    int            `json:"total" xml:"total"`
+	Overdue  int            `json:"overdue" xml:"overdue"`
+	DueToday int            `json:"due_today" xml:"due_today"`
+}
+
+func (s *Server) handleGetTasks(w http.ResponseWriter, r *http.Request) {
+	search := r.URL.Query().Get("search")
+	starredOnly := r.URL.Query().Get("starred") == "true"
+
+	color := storage.TaskColor(r.URL.Query().Get("color"))
+	if !color.Valid() {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("некорректный цвет"))
+		return
+	}
+
+	limit, err := s.parseTasksLimit(r)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	recurring, err := parseRecurring(r.URL.Query().Get("recurring"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	repeatPrefix := r.URL.Query().Get("repeat_prefix")
+	if !validRepeatPrefix(repeatPrefix) {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("некорректный repeat_prefix"))
+		return
+	}
+
+	tasks, err := s.store.Tasks(r.Context(), search, limit, starredOnly, color, recurring, repeatPrefix)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	counts, err := s.store.CountTasks(r.Context(), search, nextdate.Format(time.Now()))
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	resp := tasksResponse{Total: counts.Total, Overdue: counts.Overdue, DueToday: counts.DueToday}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		resp.Tasks = tasks
+		s.writeTasksResponse(w, r, resp)
+		return
+	}
+
+	groups, err := groupTasks(tasks, groupBy)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	resp.Groups = groups
+	s.writeTasksResponse(w, r, resp)
+}
+
+// parseTasksLimit reads GET /api/tasks' ?limit= query parameter, defaulting
+// to s.tasksDefaultLimit when it's absent or 0 and clamping anything above
+// s.tasksMaxLimit down to that cap, so a client can't force an unbounded
+// response by asking for more rows.
+func (s *Server) parseTasksLimit(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return s.tasksDefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("некорректный limit: %q", raw)
+	}
+	if limit == 0 {
+		return s.tasksDefaultLimit, nil
+	}
+	if limit > s.tasksMaxLimit {
+		limit = s.tasksMaxLimit
+	}
+	return limit, nil
+}
+
+// parseRecurring parses GET /api/tasks' ?recurring= query parameter into
+// storage.Store.Tasks' tri-state filter: nil (raw == "", no filter),
+// true or false. Any other value is rejected rather than silently
+// ignored.
+func parseRecurring(raw string) (*bool, error) {
+	switch raw {
+	case "":
+		return nil, nil
+	case "true":
+		v := true
+		return &v, nil
+	case "false":
+		v := false
+		return &v, nil
+	default:
+		return nil, fmt.Errorf("некорректный recurring: %q, ожидается true или false", raw)
+	}
+}
+
+// validRepeatPrefix reports whether prefix is empty (no filter) or one of
+// the nextdate repeat-rule units.
+func validRepeatPrefix(prefix string) bool {
+	switch prefix {
+	case "", "d", "w", "m", "y":
+		return true
+	default:
+		return false
+	}
+}
+
+// writeTasksResponse writes resp in whatever format the request's Accept
+// header negotiates - see negotiateFormat - defaulting to JSON.
+func (s *Server) writeTasksResponse(w http.ResponseWriter, r *http.Request, resp tasksResponse) {
+	switch negotiateFormat(r) {
+	case "xml":
+		writeXML(w, http.StatusOK, resp)
+	case "text":
+		writeText(w, http.StatusOK, tasksResponseText(resp))
+	default:
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// tasksResponseText renders resp as one tab-separated line per task -
+// id, date, title, comment, repeat - flattening groups when group_by was
+// used, since a shell script wants the tasks, not the UI's grouping.
+func tasksResponseText(resp tasksResponse) string {
+	tasks := resp.Tasks
+	if resp.Groups != nil {
+		tasks = nil
+		for _, g := range resp.Groups {
+			tasks = append(tasks, g.Tasks...)
+		}
+	}
+	var b strings.Builder
+	for _, t := range tasks {
+		fmt.Fprintf(&b, "%d\t%s\t%s\t%s\t%s\n", t.ID, t.Date, t.Title, t.Comment, t.Repeat)
+	}
+	return b.String()
+}
+
+// groupTasks buckets tasks by groupBy, preserving each group's tasks in
+// their incoming order and ordering the groups themselves by first
+// appearance. Only "date" is supported: storage.Task has no project or
+// tag field to group by, so those values are rejected rather than
+// grouping by something that doesn't exist.
+func groupTasks(tasks []storage.Task, groupBy string) ([]taskGroup, error) {
+	if groupBy != "date" {
+		return nil, errors.New("группировка поддерживается только по date: у задачи нет полей project или tag")
+	}
+
+	var groups []taskGroup
+	index := map[string]int{}
+	for _, t := range tasks {
+		i, ok := index[t.Date]
+		if !ok {
+			i = len(groups)
+			index[t.Date] = i
+			groups = append(groups, taskGroup{Key: t.Date})
+		}
+		groups[i].Tasks = append(groups[i].Tasks, t)
+		groups[i].Count++
+	}
+	return groups, nil
+}
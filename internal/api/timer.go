@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// taskWithTrackedTime is a storage.Task annotated with fields computed
+// rather than stored on the row itself: the total minutes logged
+// against it across every time entry (see storage.TimeTracker), closed
+// or still running; its comment rendered from Markdown to sanitized HTML
+// (see markdown.Render); and the tasks it's linked to (see
+// storage.LinkStore).
+type taskWithTrackedTime struct {
+	XMLName xml.Name `json:"-" xml:"task"`
+	storage.Task
+	TrackedMinutes  int                `json:"tracked_minutes" xml:"tracked_minutes"`
+	RenderedComment string             `json:"rendered_comment" xml:"rendered_comment"`
+	Links           []storage.TaskLink `json:"links" xml:"links>link"`
+}
+
+// trackedMinutes sums storage.TimeEntry.Minutes across every entry logged
+// against taskID.
+func (s *Server) trackedMinutes(r *http.Request, taskID int64) (int, error) {
+	entries, err := s.timeTracker.TimeEntriesForTask(r.Context(), taskID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	var total int
+	for _, e := range entries {
+		total += e.Minutes(now)
+	}
+	return total, nil
+}
+
+// handleStartTimer opens a new time entry for task id, so work on it
+// starts being tracked from now. It's rejected if a timer is already
+// running for the task - stop that one first.
+func (s *Server) handleStartTimer(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.store.Task(r.Context(), id); errors.Is(err, storage.ErrNotFound) {
+		s.writeError(w, r, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	entry, err := s.timeTracker.StartTimer(r.Context(), id, time.Now())
+	if errors.Is(err, storage.ErrTimerRunning) {
+		s.writeError(w, r, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// handleStopTimer closes task id's running time entry, returning
+// storage.ErrTimerNotRunning as a 409 if none is open.
+func (s *Server) handleStopTimer(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWrite(w, r) {
+		return
+	}
+
+	id, err := parseID(r.URL.Query().Get("id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := s.timeTracker.StopTimer(r.Context(), id, time.Now())
+	if errors.Is(err, storage.ErrTimerNotRunning) {
+		s.writeError(w, r, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// timeReportRow is one task's logged time on the reported day.
+type timeReportRow struct {
+	TaskID  int64 `json:"task_id"`
+	Minutes int   `json:"minutes"`
+}
+
+// handleTimeReport reports, for the given day, how many minutes were
+// logged against each task that had any activity on it. date defaults to
+// today when omitted.
+func (s *Server) handleTimeReport(w http.ResponseWriter, r *http.Request) {
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		date = nextdate.Format(time.Now())
+	} else if _, err := nextdate.Parse(date); err != nil {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("дата представлена в некорректном формате"))
+		return
+	}
+
+	entries, err := s.timeTracker.TimeEntriesOnDate(r.Context(), date)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	totals := map[int64]int{}
+	var order []int64
+	now := time.Now()
+	for _, e := range entries {
+		if _, seen := totals[e.TaskID]; !seen {
+			order = append(order, e.TaskID)
+		}
+		totals[e.TaskID] += e.Minutes(now)
+	}
+
+	rows := make([]timeReportRow, len(order))
+	for i, taskID := range order {
+		rows[i] = timeReportRow{TaskID: taskID, Minutes: totals[taskID]}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"date": date, "tasks": rows})
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// todayTask is a storage.Task annotated with whether it's overdue
+// (dated before today) rather than due today itself.
+type todayTask struct {
+	storage.Task
+	Overdue bool `json:"overdue"`
+}
+
+// handleTodayTasks reports every task due today or earlier, most
+// overdue first, so the UI and CLI don't have to filter the full list
+// themselves. There's no priority field on storage.Task yet, so tasks
+// due on the same date keep the store's own order rather than a second
+// sort key.
+func (s *Server) handleTodayTasks(w http.ResponseWriter, r *http.Request) {
+	today := nextdate.Format(time.Now())
+
+	tasks, err := s.store.TasksDueBy(r.Context(), today)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := make([]todayTask, len(tasks))
+	for i, t := range tasks {
+		result[i] = todayTask{Task: t, Overdue: t.Date < today}
+	}
+	writeJSON(w, http.StatusOK, map[string][]todayTask{"tasks": result})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ngavrilenkov/go_final_project/internal/auth"
+)
+
+type createAPITokenRequest struct {
+	Name string `json:"name"`
+}
+
+type apiTokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// handleCreateAPIToken mints a new personal access token. The raw token
+// is returned once and never stored - only its hash is persisted.
+func (s *Server) handleCreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	var req createAPITokenRequest
+	if !s.decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		s.writeError(w, r, http.StatusBadRequest, errors.New("не указано имя токена"))
+		return
+	}
+
+	raw, err := auth.NewAPIToken()
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	id, err := s.sessions.CreateAPIToken(r.Context(), req.Name, auth.HashToken(raw), time.Now())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "token": raw})
+}
+
+func (s *Server) handleListAPITokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := s.sessions.ListAPITokens(r.Context())
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make([]apiTokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		resp = append(resp, apiTokenResponse{
+			ID:         t.ID,
+			Name:       t.Name,
+			CreatedAt:  t.CreatedAt,
+			LastUsedAt: t.LastUsedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tokens": resp})
+}
+
+func (s *Server) handleDeleteAPIToken(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.sessions.DeleteAPIToken(r.Context(), id); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+const (
+	defaultUpcomingDays = 7
+	maxUpcomingDays     = 90
+)
+
+// upcomingDay groups the tasks landing on one date within the requested
+// window, whether that's a task's own stored date or a projected
+// occurrence of a recurring task anchored earlier.
+type upcomingDay struct {
+	Date  string         `json:"date"`
+	Tasks []storage.Task `json:"tasks"`
+}
+
+// handleUpcomingTasks reports tasks due within the next days days
+// (default and minimum 1, maximum maxUpcomingDays, default
+// defaultUpcomingDays), grouped by date. Recurring tasks anchored before
+// the window are projected forward with nextdate.Next so a weekly task
+// created months ago still shows up on the days it's due this week.
+func (s *Server) handleUpcomingTasks(w http.ResponseWriter, r *http.Request) {
+	days := defaultUpcomingDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxUpcomingDays {
+			s.writeError(w, r, http.StatusBadRequest,
+				fmt.Errorf("days должен быть числом от 1 до %d", maxUpcomingDays))
+			return
+		}
+		days = v
+	}
+
+	now := time.Now()
+	from := nextdate.Format(now)
+	to := nextdate.Format(now.AddDate(0, 0, days))
+
+	byDate := map[string][]storage.Task{}
+
+	inRange, err := s.store.TasksInRange(r.Context(), from, to)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	for _, t := range inRange {
+		byDate[t.Date] = append(byDate[t.Date], t)
+	}
+
+	priorRecurring, err := s.store.TasksDueBy(r.Context(), from)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	for _, t := range priorRecurring {
+		if t.Repeat == "" || t.Date >= from {
+			continue
+		}
+		projectOccurrences(t, from, to, byDate)
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	result := make([]upcomingDay, len(dates))
+	for i, d := range dates {
+		result[i] = upcomingDay{Date: d, Tasks: byDate[d]}
+	}
+	writeJSON(w, http.StatusOK, map[string][]upcomingDay{"days": result})
+}
+
+// projectOccurrences walks t's repeat rule forward from t's own date,
+// adding every occurrence landing within [from, to] to byDate.
+func projectOccurrences(t storage.Task, from, to string, byDate map[string][]storage.Task) {
+	cur := t.Date
+	for {
+		curTime, err := nextdate.Parse(cur)
+		if err != nil {
+			return
+		}
+		next, err := nextdate.Next(curTime, t.Date, t.Repeat)
+		if err != nil || next > to {
+			return
+		}
+		if next >= from {
+			occurrence := t
+			occurrence.Date = next
+			byDate[next] = append(byDate[next], occurrence)
+		}
+		cur = next
+	}
+}
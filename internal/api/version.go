@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ngavrilenkov/go_final_project/internal/version"
+)
+
+type versionResponse struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
+// handleVersion reports the running build, so a user reporting a bug can
+// be asked which one they're on.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, versionResponse{
+		Version: version.Version,
+		Commit:  version.Commit,
+		Date:    version.Date,
+	})
+}
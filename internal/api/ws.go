@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ngavrilenkov/go_final_project/internal/api/middleware"
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+// wsUpgrader has no origin restriction beyond the default same-origin
+// check disabled: CORS is already enforced on this route the same way as
+// the rest of the API (see middleware.CORS), and mobile/native clients
+// send no Origin header at all.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsCommand is a client-sent instruction over the /api/ws connection.
+type wsCommand struct {
+	Action string      `json:"action"`
+	Task   taskRequest `json:"task,omitempty"`
+	ID     string      `json:"id,omitempty"`
+}
+
+// wsMessage is a server-sent frame: either a domain event pushed
+// asynchronously or the result of a command the client sent.
+type wsMessage struct {
+	Type  string        `json:"type"`
+	Event *events.Event `json:"event,omitempty"`
+	ID    int64         `json:"id,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// handleWS upgrades the connection to a WebSocket that both pushes task
+// lifecycle events, like GET /api/events, and accepts "add"/"done"
+// commands from the client, so an interactive client can use a single
+// connection instead of a stream plus separate REST calls.
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already wrote its own error response.
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	send := make(chan wsMessage, eventStreamBuffer)
+	unsubscribe := s.events.Subscribe(func(_ context.Context, event events.Event) {
+		e := event
+		select {
+		case send <- wsMessage{Type: "event", Event: &e}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	go func() {
+		defer cancel()
+		s.wsReadLoop(ctx, conn, send)
+	}()
+
+	s.wsWriteLoop(ctx, conn, send)
+}
+
+// wsReadLoop reads commands from conn until it errors or ctx is done,
+// answering each on send. It also refreshes the read deadline on every
+// pong, the other half of the ping/pong keepalive driven by wsWriteLoop.
+func (s *Server) wsReadLoop(ctx context.Context, conn *websocket.Conn, send chan<- wsMessage) {
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var cmd wsCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			return
+		}
+		select {
+		case send <- s.handleWSCommand(ctx, cmd):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// wsWriteLoop is the connection's sole writer: gorilla/websocket doesn't
+// allow concurrent writes, so both queued messages and keepalive pings go
+// through this one loop.
+func (s *Server) wsWriteLoop(ctx context.Context, conn *websocket.Conn, send <-chan wsMessage) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-send:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleWSCommand runs a client command and reports its outcome. ctx
+// carries the permission ValidateTokenMiddleware assigned the connection
+// at upgrade time (see middleware.PermissionFrom).
+func (s *Server) handleWSCommand(ctx context.Context, cmd wsCommand) wsMessage {
+	if middleware.PermissionFrom(ctx) != storage.PermissionWrite {
+		return wsMessage{Type: "error", Error: errReadOnly.Error()}
+	}
+
+	switch cmd.Action {
+	case "add":
+		t, err := prepareTask(storage.Task{}, cmd.Task)
+		if err != nil {
+			return wsMessage{Type: "error", Error: err.Error()}
+		}
+		id, err := s.store.AddTask(ctx, t)
+		if err != nil {
+			return wsMessage{Type: "error", Error: err.Error()}
+		}
+		return wsMessage{Type: "result", ID: id}
+
+	case "done":
+		id, err := parseID(cmd.ID)
+		if err != nil {
+			return wsMessage{Type: "error", Error: err.Error()}
+		}
+		t, err := s.store.Task(ctx, id)
+		if err != nil {
+			return wsMessage{Type: "error", Error: err.Error()}
+		}
+		var next string
+		if t.Repeat != "" {
+			next, err = nextdate.Next(time.Now(), t.Date, t.Repeat)
+			if err != nil {
+				return wsMessage{Type: "error", Error: err.Error()}
+			}
+		}
+		if err := s.store.CompleteTask(ctx, t, next); err != nil {
+			return wsMessage{Type: "error", Error: err.Error()}
+		}
+		return wsMessage{Type: "result", ID: id}
+
+	default:
+		return wsMessage{Type: "error", Error: fmt.Sprintf("неизвестное действие %q", cmd.Action)}
+	}
+}
@@ -0,0 +1,93 @@
+// Package archive exports and restores the scheduler's full dataset as a
+// single versioned JSON document, so a deployment can move its data
+// between storage backends without going through either one's native
+// format.
+package archive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Version identifies the archive document's shape, so a future backend
+// with a different schema can tell whether it knows how to restore a
+// given archive rather than guessing from its contents.
+const Version = 1
+
+// Archive is the full contents of the scheduler's dataset. It covers
+// everything the current schema stores - tasks, reminder delivery
+// history, logged time entries, task notes and task links - and will
+// grow new fields as the schema does; there is no tags or settings data
+// to include yet.
+type Archive struct {
+	Version       int                    `json:"version"`
+	Tasks         []storage.Task         `json:"tasks"`
+	SentReminders []storage.SentReminder `json:"sent_reminders"`
+	TimeEntries   []storage.TimeEntry    `json:"time_entries"`
+	Notes         []storage.TaskNote     `json:"notes"`
+	Links         []storage.TaskLink     `json:"links"`
+}
+
+// Export reads the entire dataset out of store, reminders, timeTracker,
+// notes and links into an Archive.
+func Export(ctx context.Context, store storage.Store, reminders storage.ReminderStore, timeTracker storage.TimeTracker, notes storage.NoteStore, links storage.LinkStore) (Archive, error) {
+	tasks := []storage.Task{}
+	err := store.StreamTasks(ctx, func(t storage.Task) error {
+		tasks = append(tasks, t)
+		return nil
+	})
+	if err != nil {
+		return Archive{}, fmt.Errorf("export tasks: %w", err)
+	}
+
+	sent, err := reminders.AllSentReminders(ctx)
+	if err != nil {
+		return Archive{}, fmt.Errorf("export sent reminders: %w", err)
+	}
+
+	entries, err := timeTracker.AllTimeEntries(ctx)
+	if err != nil {
+		return Archive{}, fmt.Errorf("export time entries: %w", err)
+	}
+
+	allNotes, err := notes.AllNotes(ctx)
+	if err != nil {
+		return Archive{}, fmt.Errorf("export notes: %w", err)
+	}
+
+	allLinks, err := links.AllLinks(ctx)
+	if err != nil {
+		return Archive{}, fmt.Errorf("export links: %w", err)
+	}
+
+	return Archive{Version: Version, Tasks: tasks, SentReminders: sent, TimeEntries: entries, Notes: allNotes, Links: allLinks}, nil
+}
+
+// Restore writes a's tasks, reminder history, time entries, notes and
+// links into store, reminders, timeTracker, notes and links, preserving
+// each task's original ID so the reminder history, time entries, notes
+// and links still point at the right task afterwards. It refuses an
+// archive written by a version it doesn't understand.
+func Restore(ctx context.Context, store storage.Store, reminders storage.ReminderStore, timeTracker storage.TimeTracker, notes storage.NoteStore, links storage.LinkStore, a Archive) error {
+	if a.Version != Version {
+		return fmt.Errorf("unsupported archive version %d, want %d", a.Version, Version)
+	}
+	if err := store.RestoreTasks(ctx, a.Tasks); err != nil {
+		return fmt.Errorf("restore tasks: %w", err)
+	}
+	if err := reminders.RestoreSentReminders(ctx, a.SentReminders); err != nil {
+		return fmt.Errorf("restore sent reminders: %w", err)
+	}
+	if err := timeTracker.RestoreTimeEntries(ctx, a.TimeEntries); err != nil {
+		return fmt.Errorf("restore time entries: %w", err)
+	}
+	if err := notes.RestoreNotes(ctx, a.Notes); err != nil {
+		return fmt.Errorf("restore notes: %w", err)
+	}
+	if err := links.RestoreLinks(ctx, a.Links); err != nil {
+		return fmt.Errorf("restore links: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+// Package auth implements the scheduler's password-based session flow:
+// short-lived JWT access tokens backed by longer-lived refresh tokens.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// AccessTTL is how long an issued access token stays valid.
+	AccessTTL = 15 * time.Minute
+	// RefreshTTL is how long an issued refresh token stays valid.
+	RefreshTTL = 30 * 24 * time.Hour
+)
+
+// ErrInvalidToken is returned when an access token fails verification.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// claims embeds a hash of the configured password so that changing the
+// password invalidates every access token issued under the old one.
+// Subject identifies who the token was issued for: empty for the owner
+// (password sign-in), or a collaborator's username. Permission is the
+// access level that identity was granted on the shared task list.
+type claims struct {
+	PasswordHash string `json:"pwd_hash"`
+	Permission   string `json:"perm"`
+	jwt.RegisteredClaims
+}
+
+// AccessClaims are the identity and permission facts carried by a valid
+// access token.
+type AccessClaims struct {
+	JTI        string
+	Subject    string
+	Permission string
+}
+
+// Manager issues and verifies access tokens for a single configured
+// password.
+type Manager struct {
+	mu        sync.RWMutex
+	password  string
+	jwtSecret string
+}
+
+// NewManager builds a Manager for the given password. jwtSecret is mixed
+// into the token signing key on top of the password - typically loaded
+// from TODO_JWT_SECRET_FILE - and may be empty, in which case the
+// password alone derives the key as before.
+func NewManager(password, jwtSecret string) *Manager {
+	return &Manager{password: password, jwtSecret: jwtSecret}
+}
+
+// SetPassword replaces the password Manager checks sign-ins against and
+// stamps new access tokens with. Every token issued under the old
+// password stops verifying immediately, since ParseAccessToken compares
+// against the current password's hash (see claims.PasswordHash) - used
+// to apply a changed password without restarting the server.
+func (m *Manager) SetPassword(password string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.password = password
+}
+
+// SetJWTSecret replaces the pepper mixed into the token signing key.
+// Like SetPassword, this invalidates every token issued under the old
+// secret immediately - used to apply a rotated TODO_JWT_SECRET_FILE
+// without restarting the server.
+func (m *Manager) SetJWTSecret(jwtSecret string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jwtSecret = jwtSecret
+}
+
+// CheckPassword reports whether password matches the configured one, in
+// constant time so a timing attack can't be used to guess it a byte at a
+// time.
+func (m *Manager) CheckPassword(password string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return subtle.ConstantTimeCompare([]byte(password), []byte(m.password)) == 1
+}
+
+// NewAccessToken issues a signed, short-lived access token for subject
+// (empty for the owner) with the given permission, and returns its jti
+// alongside it, so callers can later revoke that specific token.
+func (m *Manager) NewAccessToken(subject, permission string) (token, jti string, err error) {
+	jti, err = randomHex(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	c := claims{
+		PasswordHash: m.passwordHash(),
+		Permission:   permission,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTTL)),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(m.secret())
+	if err != nil {
+		return "", "", fmt.Errorf("sign access token: %w", err)
+	}
+	return signed, jti, nil
+}
+
+// VerifyAccessToken returns nil if token is a currently valid access
+// token for the configured password, or ErrInvalidToken otherwise.
+func (m *Manager) VerifyAccessToken(token string) error {
+	_, err := m.ParseAccessToken(token)
+	return err
+}
+
+// ParseAccessToken validates token and returns the identity and
+// permission it carries, so callers can check its jti against a
+// revocation list and enforce its permission.
+func (m *Manager) ParseAccessToken(token string) (AccessClaims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &claims{}, func(*jwt.Token) (any, error) {
+		return m.secret(), nil
+	})
+	if err != nil || !parsed.Valid {
+		return AccessClaims{}, ErrInvalidToken
+	}
+	c, ok := parsed.Claims.(*claims)
+	if !ok || c.PasswordHash != m.passwordHash() {
+		return AccessClaims{}, ErrInvalidToken
+	}
+	return AccessClaims{JTI: c.ID, Subject: c.Subject, Permission: c.Permission}, nil
+}
+
+// NewRefreshToken generates a random opaque refresh token. Callers are
+// responsible for persisting it, e.g. via storage.RefreshTokenStore.
+func NewRefreshToken() (string, error) {
+	return randomHex(32)
+}
+
+// apiTokenPrefix marks a token as a long-lived personal access token, so
+// it can be told apart from a JWT access token at a glance.
+const apiTokenPrefix = "sched_pat_"
+
+// NewAPIToken generates a random personal access token. Callers are
+// responsible for persisting its hash via storage.APITokenStore.
+func NewAPIToken() (string, error) {
+	raw, err := randomHex(24)
+	if err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + raw, nil
+}
+
+// IsAPIToken reports whether token looks like a personal access token
+// rather than a JWT access token.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, apiTokenPrefix)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the digest under which a refresh token should be
+// stored, so the raw token value never touches disk.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Password KDF parameters, chosen to match the scrypt cost already used
+// for backup passphrases in internal/backupcrypto.
+const (
+	passwordSaltSize = 16
+	passwordKeyLen   = 32
+	passwordScryptN  = 1 << 15
+	passwordScryptR  = 8
+	passwordScryptP  = 1
+)
+
+// HashPassword derives a salted scrypt digest under which a collaborator's
+// password should be stored, encoding the salt and KDF cost alongside the
+// digest as "scrypt$N$r$p$saltHex$hashHex" so VerifyPassword can check it
+// later without the cost parameters changing out from under stored
+// hashes. Unlike HashToken, a password is low-entropy and guessable, so it
+// needs a slow, salted KDF rather than a bare digest.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	key, err := scrypt.Key([]byte(password), salt, passwordScryptN, passwordScryptR, passwordScryptP, passwordKeyLen)
+	if err != nil {
+		return "", fmt.Errorf("derive key: %w", err)
+	}
+	return fmt.Sprintf("scrypt$%d$%d$%d$%s$%s",
+		passwordScryptN, passwordScryptR, passwordScryptP,
+		hex.EncodeToString(salt), hex.EncodeToString(key)), nil
+}
+
+// VerifyPassword reports whether password matches stored, a digest
+// produced by HashPassword.
+func VerifyPassword(password, stored string) bool {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 || parts[0] != "scrypt" {
+		return false
+	}
+	n, errN := strconv.Atoi(parts[1])
+	r, errR := strconv.Atoi(parts[2])
+	p, errP := strconv.Atoi(parts[3])
+	if errN != nil || errR != nil || errP != nil {
+		return false
+	}
+	salt, err := hex.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	want, err := hex.DecodeString(parts[5])
+	if err != nil {
+		return false
+	}
+	got, err := scrypt.Key([]byte(password), salt, n, r, p, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+func (m *Manager) secret() []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sum := sha256.Sum256([]byte("go_final_project:" + m.password + m.jwtSecret))
+	return sum[:]
+}
+
+func (m *Manager) passwordHash() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sum := sha256.Sum256([]byte(m.password))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// loginLockoutBase is the lockout duration after the first failed
+	// attempt; it doubles with each further consecutive failure.
+	loginLockoutBase = time.Second
+	// loginLockoutMax caps how long a single key can be locked out for.
+	loginLockoutMax = 15 * time.Minute
+	// maxLoginFailures caps the shift used to compute the backoff, so it
+	// can't overflow before hitting loginLockoutMax.
+	maxLoginFailures = 30
+)
+
+// GlobalLoginKey is the key RecordFailure/Allow should be called with in
+// addition to the caller's own key, so a distributed flood of low attempts
+// per IP still gets throttled overall.
+const GlobalLoginKey = "*"
+
+// LoginLimiter throttles repeated failed sign-in attempts per key (e.g. a
+// client IP, or GlobalLoginKey), so the sign-in endpoint can't be used as
+// an unthrottled password oracle. A successful attempt resets a key's
+// failure count; failures back off exponentially, up to loginLockoutMax.
+type LoginLimiter struct {
+	mu       sync.Mutex
+	attempts map[string]*loginAttempts
+}
+
+type loginAttempts struct {
+	failures    int
+	lockedUntil time.Time
+}
+
+// NewLoginLimiter returns a LoginLimiter with no recorded attempts.
+func NewLoginLimiter() *LoginLimiter {
+	return &LoginLimiter{attempts: make(map[string]*loginAttempts)}
+}
+
+// Allow reports whether key may attempt a sign-in right now. If not, it
+// also returns how long the caller should wait before retrying.
+func (l *LoginLimiter) Allow(key string) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, exists := l.attempts[key]
+	if !exists {
+		return true, 0
+	}
+	if wait := time.Until(a.lockedUntil); wait > 0 {
+		return false, wait
+	}
+	return true, 0
+}
+
+// RecordFailure counts a failed sign-in attempt for key and extends its
+// lockout exponentially.
+func (l *LoginLimiter) RecordFailure(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, exists := l.attempts[key]
+	if !exists {
+		a = &loginAttempts{}
+		l.attempts[key] = a
+	}
+	if a.failures < maxLoginFailures {
+		a.failures++
+	}
+	lockout := loginLockoutBase << uint(a.failures-1)
+	if lockout <= 0 || lockout > loginLockoutMax {
+		lockout = loginLockoutMax
+	}
+	a.lockedUntil = time.Now().Add(lockout)
+}
+
+// RecordSuccess clears key's failure history.
+func (l *LoginLimiter) RecordSuccess(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, key)
+}
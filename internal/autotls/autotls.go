@@ -0,0 +1,183 @@
+// Package autotls provides a self-managed TLS certificate for the
+// scheduler's HTTPS listener.
+//
+// The natural fit here is golang.org/x/crypto/acme/autocert, which
+// obtains and renews certificates from Let's Encrypt: that module isn't
+// vendored in this build environment, so Manager instead generates and
+// caches a self-signed certificate for Domain, renewing it automatically
+// before it expires. It exposes the same two seams a real ACME manager
+// would (TLSConfig for the HTTPS listener, HTTPHandler for the port-80
+// listener), so swapping in autocert.Manager later only touches New.
+package autotls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// certLifetime matches the validity window Let's Encrypt issues, so
+// renewal timing behaves the same once a real ACME provider replaces
+// this.
+const certLifetime = 90 * 24 * time.Hour
+
+// renewBefore triggers renewal this long before expiry.
+const renewBefore = 30 * 24 * time.Hour
+
+// Manager serves a certificate for Domain, generating and caching it in
+// CacheDir on first use and renewing it as it approaches expiry.
+type Manager struct {
+	Domain   string
+	CacheDir string
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+}
+
+// New returns a Manager for domain, caching its certificate under
+// cacheDir.
+func New(domain, cacheDir string) *Manager {
+	return &Manager{Domain: domain, CacheDir: cacheDir}
+}
+
+// TLSConfig returns a tls.Config that serves (and transparently renews)
+// this manager's certificate.
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{GetCertificate: m.getCertificate}
+}
+
+// HTTPHandler wraps fallback with a redirect to the HTTPS site. A real
+// ACME provider would instead answer the HTTP-01 challenge here before
+// falling back to this same redirect.
+func (m *Manager) HTTPHandler(fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := "https://" + m.Domain + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
+
+// Reload discards the cached certificate, so the next handshake re-reads
+// it from CacheDir instead of waiting for the renewal window - used to
+// pick up a certificate/key replaced on disk without restarting the
+// server.
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cert = nil
+}
+
+func (m *Manager) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cert != nil && !needsRenewal(m.cert) {
+		return m.cert, nil
+	}
+
+	cert, err := m.loadCached()
+	if err == nil && !needsRenewal(cert) {
+		m.cert = cert
+		return m.cert, nil
+	}
+
+	cert, err = m.issue()
+	if err != nil {
+		return nil, fmt.Errorf("issue certificate for %s: %w", m.Domain, err)
+	}
+	m.cert = cert
+	return m.cert, nil
+}
+
+func needsRenewal(cert *tls.Certificate) bool {
+	leaf := cert.Leaf
+	if leaf == nil {
+		x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return true
+		}
+		leaf = x509Cert
+	}
+	return time.Now().After(leaf.NotAfter.Add(-renewBefore))
+}
+
+func (m *Manager) loadCached() (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(m.certPath(), m.keyPath())
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf = leaf
+	return &cert, nil
+}
+
+func (m *Manager) issue() (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("generate serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: m.Domain},
+		DNSNames:     []string{m.Domain},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(certLifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate: %w", err)
+	}
+
+	if err := m.cacheKeyPair(der, key); err != nil {
+		return nil, fmt.Errorf("cache certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+func (m *Manager) cacheKeyPair(der []byte, key *ecdsa.PrivateKey) error {
+	if err := os.MkdirAll(m.CacheDir, 0o700); err != nil {
+		return err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+
+	if err := os.WriteFile(m.certPath(), certPEM, 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(m.keyPath(), keyPEM, 0o600)
+}
+
+func (m *Manager) certPath() string { return filepath.Join(m.CacheDir, m.Domain+".crt") }
+func (m *Manager) keyPath() string  { return filepath.Join(m.CacheDir, m.Domain+".key") }
@@ -0,0 +1,202 @@
+// Package backup periodically writes timestamped snapshots of the
+// database to a directory and prunes old ones, so operators get
+// automatic off-server-state backups without wiring up cron themselves.
+// Snapshots can optionally be encrypted (see internal/backupcrypto) and
+// shipped to off-server storage before pruning.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Uploader ships a backup's bytes to off-server storage under key. A nil
+// Uploader is valid; Worker treats it as "no off-server copy configured"
+// and only ever writes to disk.
+type Uploader interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// Encrypter transforms a backup's plaintext bytes before they're written
+// to disk or handed to an Uploader. A nil Encrypter is valid; Worker
+// treats it as "no encryption configured" and leaves backups as plain
+// SQLite files.
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+}
+
+// filePrefix and fileSuffix bound the timestamped names Worker writes
+// and prunes, so it never touches a file it didn't create itself.
+const (
+	filePrefix = "backup-"
+	fileSuffix = ".db"
+	// timeLayout is embedded in each backup's filename and also used to
+	// order them for retention, so it must sort the same way
+	// lexically and chronologically.
+	timeLayout = "20060102-150405"
+)
+
+// Config holds the settings a Worker needs to take and prune scheduled
+// backups.
+type Config struct {
+	// Dir is the directory backup files are written to. Must be set and
+	// writable; New verifies this up front rather than failing on the
+	// first scheduled run.
+	Dir string
+
+	// Interval is how often a backup is taken. Empty (0) is invalid;
+	// New rejects it.
+	Interval time.Duration
+
+	// RetentionCount keeps at most this many of the newest backups,
+	// deleting older ones after each run. Zero disables count-based
+	// pruning.
+	RetentionCount int
+
+	// RetentionAge deletes backups older than this after each run.
+	// Zero disables age-based pruning.
+	RetentionAge time.Duration
+}
+
+// Worker takes a snapshot of a database on Config.Interval, prunes old
+// ones under Config.RetentionCount and Config.RetentionAge, and, if
+// configured, encrypts each snapshot before it's written to disk and
+// ships it off-server via an Uploader.
+type Worker struct {
+	cfg        Config
+	maintainer storage.Maintainer
+	uploader   Uploader
+	encrypter  Encrypter
+}
+
+// New returns a Worker backing up via maintainer into cfg.Dir on
+// cfg.Interval. It verifies cfg.Dir exists and is writable before
+// returning, so a misconfigured backup target is caught at startup
+// rather than on the first scheduled run. uploader and encrypter may
+// both be nil to keep backups local and unencrypted.
+func New(cfg Config, maintainer storage.Maintainer, uploader Uploader, encrypter Encrypter) (*Worker, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("backup: directory is required")
+	}
+	if cfg.Interval <= 0 {
+		return nil, fmt.Errorf("backup: interval must be positive")
+	}
+	if err := checkWritable(cfg.Dir); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	return &Worker{cfg: cfg, maintainer: maintainer, uploader: uploader, encrypter: encrypter}, nil
+}
+
+// checkWritable confirms dir exists and accepts a new file, without
+// leaving anything behind.
+func checkWritable(dir string) error {
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	name := probe.Name()
+	probe.Close()
+	return os.Remove(name)
+}
+
+// RunOnce takes one backup, encrypts it if configured, uploads it
+// off-server if configured, and prunes old local ones. Scheduling a
+// recurring backup at w.cfg.Interval is the caller's job - see
+// jobs.Scheduler.
+func (w *Worker) RunOnce(ctx context.Context) error {
+	name := filePrefix + time.Now().UTC().Format(timeLayout) + fileSuffix
+	path := filepath.Join(w.cfg.Dir, name)
+	if report, err := w.maintainer.Backup(ctx, path); err != nil {
+		return fmt.Errorf("take backup: %w", err)
+	} else if !report.OK {
+		return fmt.Errorf("take backup: %s", report.Message)
+	}
+
+	if w.encrypter != nil {
+		plaintext, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read backup for encryption: %w", err)
+		}
+		ciphertext, err := w.encrypter.Encrypt(plaintext)
+		if err != nil {
+			return fmt.Errorf("encrypt backup: %w", err)
+		}
+		if err := os.WriteFile(path, ciphertext, 0o600); err != nil {
+			return fmt.Errorf("write encrypted backup: %w", err)
+		}
+	}
+
+	if w.uploader != nil {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read backup for upload: %w", err)
+		}
+		if err := w.uploader.Put(ctx, name, data); err != nil {
+			return fmt.Errorf("upload backup: %w", err)
+		}
+	}
+	if err := w.prune(); err != nil {
+		return fmt.Errorf("prune old backups: %w", err)
+	}
+	return nil
+}
+
+// prune deletes backups beyond w.cfg.RetentionCount and older than
+// w.cfg.RetentionAge, applying whichever limits are non-zero.
+func (w *Worker) prune() error {
+	if w.cfg.RetentionCount <= 0 && w.cfg.RetentionAge <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(w.cfg.Dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			names = append(names, name)
+		}
+	}
+	// Filenames embed a sortable timestamp, so lexical order is
+	// chronological order; newest last.
+	sort.Strings(names)
+
+	toDelete := map[string]bool{}
+	if w.cfg.RetentionCount > 0 && len(names) > w.cfg.RetentionCount {
+		for _, name := range names[:len(names)-w.cfg.RetentionCount] {
+			toDelete[name] = true
+		}
+	}
+	if w.cfg.RetentionAge > 0 {
+		cutoff := time.Now().Add(-w.cfg.RetentionAge)
+		for _, name := range names {
+			stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+			t, err := time.Parse(timeLayout, stamp)
+			if err != nil {
+				continue
+			}
+			if t.Before(cutoff) {
+				toDelete[name] = true
+			}
+		}
+	}
+
+	for name := range toDelete {
+		if err := os.Remove(filepath.Join(w.cfg.Dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
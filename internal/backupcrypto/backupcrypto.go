@@ -0,0 +1,53 @@
+// Package backupcrypto encrypts backup files with either a shared
+// passphrase or an age-style X25519 public key, so a backup sitting on
+// disk or in an S3 bucket isn't a plaintext copy of every task in the
+// scheduler.
+//
+// The age recipient/identity key format (the "age1..." and
+// "AGE-SECRET-KEY-1..." bech32 strings) is reused because it's a
+// convenient, widely-recognized way to hand out an X25519 keypair, but
+// the ciphertext this package produces is this repo's own format, not
+// the age file format: the age library (filippo.io/age) requires a
+// newer Go toolchain than this repo is pinned to, so encryption here is
+// implemented directly over the same primitives (X25519, HKDF,
+// ChaCha20-Poly1305) rather than vendoring it. Files written by this
+// package will not decrypt with the age CLI; use "todo backup decrypt".
+package backupcrypto
+
+import "fmt"
+
+// Cipher encrypts backup bytes before they're written to disk or
+// uploaded.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+}
+
+// Config selects how backups are encrypted. Exactly one of Passphrase
+// or AgeRecipient should be set; New rejects both being set at once.
+type Config struct {
+	// Passphrase, if set, encrypts backups with a key derived from it.
+	// The same passphrase decrypts them via "todo backup decrypt
+	// -passphrase".
+	Passphrase string
+
+	// AgeRecipient, if set, encrypts backups to this X25519 public key
+	// (an "age1..." string). Only the holder of the matching identity
+	// (an "AGE-SECRET-KEY-1..." string) can decrypt them, via
+	// "todo backup decrypt -identity".
+	AgeRecipient string
+}
+
+// New returns the Cipher cfg selects, or nil if neither field is set,
+// disabling encryption.
+func New(cfg Config) (Cipher, error) {
+	switch {
+	case cfg.Passphrase != "" && cfg.AgeRecipient != "":
+		return nil, fmt.Errorf("backupcrypto: passphrase and age recipient are mutually exclusive")
+	case cfg.Passphrase != "":
+		return newPassphraseCipher(cfg.Passphrase), nil
+	case cfg.AgeRecipient != "":
+		return newRecipientCipher(cfg.AgeRecipient)
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,123 @@
+package backupcrypto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bech32 implements the encoding age keys use (BIP-173's bech32, not the
+// bech32m variant), just enough to parse "age1..." recipients and
+// "AGE-SECRET-KEY-1..." identities.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	generators := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generators[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		out = append(out, byte(c)>>5)
+	}
+	out = append(out, 0)
+	for _, c := range hrp {
+		out = append(out, byte(c)&31)
+	}
+	return out
+}
+
+func bech32VerifyChecksum(hrp string, data []byte) bool {
+	values := append(bech32HRPExpand(hrp), data...)
+	return bech32Polymod(values) == 1
+}
+
+func bech32CreateChecksum(hrp string, data []byte) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := bech32Polymod(values) ^ 1
+	checksum := make([]byte, 6)
+	for i := 0; i < 6; i++ {
+		checksum[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+	return checksum
+}
+
+// bech32Decode splits s into its human-readable part and payload,
+// verifying the checksum. It's case-insensitive, per the spec, but
+// rejects mixed-case input.
+func bech32Decode(s string) (hrp string, data []byte, err error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("bech32: mixed case")
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, fmt.Errorf("bech32: malformed string %q", s)
+	}
+	hrp = s[:pos]
+	payload := s[pos+1:]
+
+	data = make([]byte, 0, len(payload))
+	for _, c := range payload {
+		idx := strings.IndexRune(bech32Charset, c)
+		if idx < 0 {
+			return "", nil, fmt.Errorf("bech32: invalid character %q", c)
+		}
+		data = append(data, byte(idx))
+	}
+	if !bech32VerifyChecksum(hrp, data) {
+		return "", nil, fmt.Errorf("bech32: invalid checksum")
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// bech32Encode is the inverse of bech32Decode.
+func bech32Encode(hrp string, data []byte) string {
+	checksum := bech32CreateChecksum(hrp, data)
+	combined := append(append([]byte{}, data...), checksum...)
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, d := range combined {
+		b.WriteByte(bech32Charset[d])
+	}
+	return b.String()
+}
+
+// convertBits regroups a slice of fromBits-wide values into toBits-wide
+// values, as bech32 payloads do to pack arbitrary bytes into 5-bit
+// groups (and back).
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxVal := uint32(1)<<toBits - 1
+	var out []byte
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("bech32: invalid data value %d", value)
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits)&byte(maxVal))
+		}
+	}
+	if pad && bits > 0 {
+		out = append(out, byte(acc<<(toBits-bits))&byte(maxVal))
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxVal != 0 {
+		return nil, fmt.Errorf("bech32: invalid padding")
+	}
+	return out, nil
+}
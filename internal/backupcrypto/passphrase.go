@@ -0,0 +1,99 @@
+package backupcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// passphraseMagic tags a passphrase-encrypted file so decrypt can tell
+// it apart from an age-recipient-encrypted one without being told which
+// mode was used.
+var passphraseMagic = [4]byte{'T', 'B', 'P', '1'}
+
+const (
+	scryptSaltSize = 16
+	scryptN        = 1 << 15
+	scryptR        = 8
+	scryptP        = 1
+	aesKeySize     = 32
+)
+
+type passphraseCipher struct {
+	passphrase string
+}
+
+func newPassphraseCipher(passphrase string) *passphraseCipher {
+	return &passphraseCipher{passphrase: passphrase}
+}
+
+// Encrypt derives a key from the passphrase with a fresh random salt
+// (via scrypt) and seals plaintext with AES-256-GCM, writing
+// magic || salt || nonce || ciphertext.
+func (c *passphraseCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, scryptSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	key, err := scrypt.Key([]byte(c.passphrase), salt, scryptN, scryptR, scryptP, aesKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("build cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("build gcm: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(passphraseMagic)+len(salt)+len(nonce)+len(plaintext)+gcm.Overhead())
+	out = append(out, passphraseMagic[:]...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, plaintext, nil)
+	return out, nil
+}
+
+// DecryptPassphrase reverses passphraseCipher.Encrypt.
+func DecryptPassphrase(ciphertext []byte, passphrase string) ([]byte, error) {
+	if len(ciphertext) < len(passphraseMagic) || string(ciphertext[:len(passphraseMagic)]) != string(passphraseMagic[:]) {
+		return nil, fmt.Errorf("backupcrypto: not a passphrase-encrypted file")
+	}
+	rest := ciphertext[len(passphraseMagic):]
+	if len(rest) < scryptSaltSize {
+		return nil, fmt.Errorf("backupcrypto: truncated file")
+	}
+	salt, rest := rest[:scryptSaltSize], rest[scryptSaltSize:]
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, aesKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("build cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("build gcm: %w", err)
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, fmt.Errorf("backupcrypto: truncated file")
+	}
+	nonce, sealed := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: wrong passphrase or corrupt file: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,231 @@
+// Package bench generates synthetic load against a storage.Store to
+// measure latency of the repository's read paths - listing, searching,
+// counting and single-task lookups - without going through HTTP. It
+// backs the "bench" subcommand (see cmd_bench.go), for catching
+// performance regressions in the repository and search paths.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Op is one kind of repository call Run can replay.
+type Op string
+
+const (
+	// OpList calls Store.Tasks with no search term - the plain listing
+	// the web UI loads on every page view.
+	OpList Op = "list"
+	// OpSearch calls Store.Tasks with a keyword drawn from the
+	// synthetic tasks' titles - the search box's query path.
+	OpSearch Op = "search"
+	// OpGet calls Store.Task for a single synthetic task by id.
+	OpGet Op = "get"
+	// OpCount calls Store.CountTasks with no search term.
+	OpCount Op = "count"
+)
+
+// keywords seed both the synthetic tasks' titles and OpSearch's random
+// query terms, so searches are guaranteed to match a realistic subset
+// of the dataset rather than nothing at all.
+var keywords = []string{"отчёт", "встреча", "клиент", "релиз", "бюджет", "договор", "ревью", "письмо"}
+
+// Mix maps each Op to its relative weight in the replayed workload -
+// e.g. {OpList: 5, OpGet: 2} sends five list calls for every two get
+// calls.
+type Mix map[Op]int
+
+// DefaultMix approximates the API's real traffic: listings and
+// searches dominate, single-task lookups and count queries are rarer.
+var DefaultMix = Mix{OpList: 5, OpSearch: 3, OpGet: 2, OpCount: 1}
+
+// ParseMix parses a "list=5,search=3,get=2,count=1" flag value into a
+// Mix. Ops absent from s keep no weight and are never replayed.
+func ParseMix(s string) (Mix, error) {
+	mix := Mix{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weight, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, want op=weight", part)
+		}
+		op := Op(strings.TrimSpace(name))
+		switch op {
+		case OpList, OpSearch, OpGet, OpCount:
+		default:
+			return nil, fmt.Errorf("unknown op %q, want one of list, search, get, count", name)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid weight for %q: %q", name, weight)
+		}
+		mix[op] = n
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("mix is empty")
+	}
+	return mix, nil
+}
+
+// Config controls a Run.
+type Config struct {
+	// Requests is the total number of operations to replay.
+	Requests int
+	// Concurrency is how many workers replay requests at once.
+	Concurrency int
+	// Mix weights which operations are replayed. A zero value uses
+	// DefaultMix.
+	Mix Mix
+}
+
+// SeedTasks inserts n synthetic tasks into store for a benchmark to
+// query against, each titled with a random keyword so OpSearch has
+// something realistic to find, and returns their assigned ids.
+func SeedTasks(ctx context.Context, store storage.Store, n int) ([]int64, error) {
+	today := nextdate.Format(time.Now())
+	tasks := make([]storage.Task, n)
+	for i := range tasks {
+		tasks[i] = storage.Task{
+			Date:    today,
+			Title:   fmt.Sprintf("Синтетическая задача %d: %s", i, keywords[i%len(keywords)]),
+			Comment: "сгенерировано командой bench",
+		}
+	}
+	return store.ImportTasks(ctx, tasks)
+}
+
+// OpReport summarizes one Op's replayed latencies.
+type OpReport struct {
+	Op                      Op
+	Count, Errors           int
+	Min, P50, P90, P99, Max time.Duration
+}
+
+// Run replays cfg.Requests operations, weighted by cfg.Mix and spread
+// across cfg.Concurrency workers, against store. ids supplies the task
+// ids OpGet picks from at random; an empty ids makes OpGet a no-op
+// that reports zero calls.
+func Run(ctx context.Context, store storage.Store, ids []int64, cfg Config) ([]OpReport, error) {
+	mix := cfg.Mix
+	if mix == nil {
+		mix = DefaultMix
+	}
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var pool []Op
+	for op, weight := range mix {
+		if op == OpGet && len(ids) == 0 {
+			continue
+		}
+		for i := 0; i < weight; i++ {
+			pool = append(pool, op)
+		}
+	}
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("mix has nothing left to replay (get requested with no seeded tasks?)")
+	}
+
+	type sample struct {
+		op       Op
+		duration time.Duration
+		err      error
+	}
+	samples := make(chan sample, cfg.Requests)
+	jobs := make(chan Op, cfg.Requests)
+	for i := 0; i < cfg.Requests; i++ {
+		jobs <- pool[rand.Intn(len(pool))]
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(rand.Int63()))
+			for op := range jobs {
+				start := time.Now()
+				err := replay(ctx, store, ids, op, rng)
+				samples <- sample{op: op, duration: time.Since(start), err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	byOp := map[Op][]time.Duration{}
+	errsByOp := map[Op]int{}
+	for s := range samples {
+		byOp[s.op] = append(byOp[s.op], s.duration)
+		if s.err != nil {
+			errsByOp[s.op]++
+		}
+	}
+
+	var reports []OpReport
+	for op, durations := range byOp {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		reports = append(reports, OpReport{
+			Op:     op,
+			Count:  len(durations),
+			Errors: errsByOp[op],
+			Min:    durations[0],
+			P50:    percentile(durations, 50),
+			P90:    percentile(durations, 90),
+			P99:    percentile(durations, 99),
+			Max:    durations[len(durations)-1],
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Op < reports[j].Op })
+	return reports, nil
+}
+
+// replay issues a single call to store for op.
+func replay(ctx context.Context, store storage.Store, ids []int64, op Op, rng *rand.Rand) error {
+	switch op {
+	case OpList:
+		_, err := store.Tasks(ctx, "", 50, false, "", nil, "")
+		return err
+	case OpSearch:
+		term := keywords[rng.Intn(len(keywords))]
+		_, err := store.Tasks(ctx, term, 50, false, "", nil, "")
+		return err
+	case OpGet:
+		id := ids[rng.Intn(len(ids))]
+		_, err := store.Task(ctx, id)
+		return err
+	case OpCount:
+		_, err := store.CountTasks(ctx, "", nextdate.Format(time.Now()))
+		return err
+	default:
+		return fmt.Errorf("unknown op %q", op)
+	}
+}
+
+// percentile returns the value at p percent into sorted (already
+// ascending), e.g. percentile(sorted, 99) is p99 latency.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	idx := (p * (len(sorted) - 1)) / 100
+	return sorted[idx]
+}
@@ -0,0 +1,1204 @@
+// Package config loads runtime settings for the scheduler server from the
+// process environment.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPort   = 7540
+	defaultDBFile = "scheduler.db"
+
+	// defaultDBWALAutocheckpoint matches SQLite's own built-in default.
+	defaultDBWALAutocheckpoint = 1000
+
+	// defaultDBBusyTimeout gives concurrent writers a real chance to
+	// finish before a request fails with "database is locked", instead
+	// of SQLite's own default of failing immediately.
+	defaultDBBusyTimeout = 5 * time.Second
+
+	// defaultDBMaxOpenConns bounds the connection pool well above what a
+	// single instance of this server needs, since writes are additionally
+	// serialized in-process regardless of pool size.
+	defaultDBMaxOpenConns = 10
+
+	// defaultDBMaxIdleConns keeps a handful of connections warm between
+	// requests without holding onto more than the pool typically needs.
+	defaultDBMaxIdleConns = 5
+
+	// defaultDBConnMaxLifetime of 0 lets pooled connections live
+	// indefinitely, matching database/sql's own default.
+	defaultDBConnMaxLifetime = 0
+
+	// defaultDBSlowQueryThreshold logs a repository call as slow once it
+	// takes noticeably longer than a healthy SQLite query should, without
+	// flooding the log over ordinary jitter.
+	defaultDBSlowQueryThreshold = 100 * time.Millisecond
+
+	// defaultDBWriteRetryDeadline retries a write through a few seconds
+	// of contention from another process (a backup, a CLI import)
+	// before giving up, on top of DBBusyTimeout's own per-statement
+	// wait.
+	defaultDBWriteRetryDeadline = 5 * time.Second
+
+	// defaultDBMaintenanceInterval is how often the background
+	// integrity-check-and-vacuum job runs against the database.
+	defaultDBMaintenanceInterval = 6 * time.Hour
+
+	// defaultDBMaintenanceVacuumPages of 0 lets each run reclaim as many
+	// freelist pages as are available, rather than bounding it.
+	defaultDBMaintenanceVacuumPages = 0
+
+	// defaultPprofPort is the admin port pprof listens on when enabled.
+	defaultPprofPort = 6060
+
+	// defaultRateLimitRPS and defaultRateLimitBurst throttle each API
+	// client to a sustained rate with headroom for short bursts, so a
+	// single misbehaving script can't saturate the SQLite connection.
+	defaultRateLimitRPS   = 5
+	defaultRateLimitBurst = 20
+
+	defaultCORSMethods = "GET, POST, PUT, DELETE, OPTIONS"
+	defaultCORSHeaders = "Content-Type, Authorization"
+	defaultCORSMaxAge  = 600
+
+	// defaultMaxBodyBytes bounds request bodies so a client can't force
+	// the server to buffer an arbitrarily large JSON payload.
+	defaultMaxBodyBytes = 1 << 20 // 1 MiB
+
+	// defaultRequestTimeout bounds how long a single request, including
+	// its repository calls, may run before the server cancels it.
+	defaultRequestTimeout = 10 * time.Second
+
+	// defaultShutdownTimeout bounds how long the server waits for
+	// in-flight requests to finish draining before forcing them closed.
+	defaultShutdownTimeout = 10 * time.Second
+
+	defaultTLSCacheDir      = "autocert-cache"
+	defaultHTTPRedirectPort = 80
+
+	// defaultStaticCacheMaxAge lets browsers cache the web UI's static
+	// assets for an hour, long enough to skip most repeat-visit fetches
+	// without leaving a stale bundle cached long after a deploy.
+	defaultStaticCacheMaxAge = 3600
+
+	// defaultSlackSummaryHour posts the daily Slack summary near the start
+	// of the workday in the server's local time zone.
+	defaultSlackSummaryHour = 9
+
+	// defaultIMAPPollInterval balances catching forwarded emails promptly
+	// against not hammering the mailbox provider.
+	defaultIMAPPollInterval = time.Minute
+
+	defaultIMAPMailbox = "INBOX"
+
+	// defaultGoogleSyncInterval balances catching changes made in Google
+	// Tasks promptly against staying well under Google's API rate limits.
+	defaultGoogleSyncInterval = 5 * time.Minute
+
+	// defaultBackupInterval takes a scheduled backup often enough to
+	// bound data loss without writing a fresh multi-task database every
+	// few seconds.
+	defaultBackupInterval = 24 * time.Hour
+
+	// defaultBackupRetentionCount keeps about two weeks of daily
+	// backups by default.
+	defaultBackupRetentionCount = 14
+
+	// defaultReminderLeadDays reminds a day ahead of a task's due date
+	// unless the task overrides it.
+	defaultReminderLeadDays = 1
+
+	// defaultReminderPollInterval checks for due reminders often enough
+	// that a task becoming due doesn't wait long for its reminder without
+	// scanning the database excessively.
+	defaultReminderPollInterval = 15 * time.Minute
+
+	// defaultNotifyEmailPort is the standard SMTP submission port.
+	defaultNotifyEmailPort = 587
+
+	// defaultNATSSubjectPrefix namespaces published domain events under a
+	// short, recognizable subject root.
+	defaultNATSSubjectPrefix = "tasks"
+
+	// defaultOutboxPollInterval balances delivering queued events promptly
+	// against scanning the outbox table excessively.
+	defaultOutboxPollInterval = 5 * time.Second
+
+	// defaultRetentionInterval runs the retention job about as often as
+	// the backup worker takes a scheduled backup.
+	defaultRetentionInterval = 24 * time.Hour
+
+	// defaultRetentionReminderHistoryAge keeps about a year of reminder
+	// delivery history by default.
+	defaultRetentionReminderHistoryAge = 365 * 24 * time.Hour
+
+	// defaultRetentionAuditLogAge keeps about a quarter of dispatched
+	// outbox events by default.
+	defaultRetentionAuditLogAge = 90 * 24 * time.Hour
+
+	// defaultTrashRetentionDays keeps a deleted task recoverable for a
+	// month before the retention job purges it for good.
+	defaultTrashRetentionDays = 30
+
+	// defaultTasksLimit is how many rows GET /api/tasks returns when the
+	// client doesn't ask for a specific page size.
+	defaultTasksLimit = 50
+
+	// defaultTasksMaxLimit caps how many rows GET /api/tasks returns
+	// even when the client asks for more via ?limit=, so a single
+	// request can't force a full table scan-sized response.
+	defaultTasksMaxLimit = 500
+
+	// defaultCacheTTL bounds how long a cached task list is served
+	// without a matching invalidation event, in case one is ever missed.
+	defaultCacheTTL = 5 * time.Minute
+
+	// defaultLocalCacheSize is a modest number of entries: single-node
+	// deployments this cache targets rarely have more distinct searches
+	// and page sizes in flight at once than this.
+	defaultLocalCacheSize = 128
+)
+
+// Config holds the settings the server needs to start.
+type Config struct {
+	Port int
+
+	DBFile string
+
+	// DBWALAutocheckpoint is the wal_autocheckpoint threshold, in pages,
+	// applied when opening DBFile. SQLite's own default is 1000; setting
+	// it to 0 disables SQLite's automatic checkpointing entirely, which
+	// a continuous replication tool (e.g. Litestream) that does its own
+	// checkpointing needs so it - not SQLite - controls when WAL frames
+	// get folded into the main database file.
+	DBWALAutocheckpoint int
+
+	// DBBusyTimeout is how long a connection waits on a locked database
+	// before giving up, applied when opening DBFile. SQLite's own
+	// default is 0 (fail immediately), which surfaces as spurious
+	// "database is locked" errors under concurrent writes.
+	DBBusyTimeout time.Duration
+
+	// DBForeignKeys enables foreign key constraint enforcement, off by
+	// default in SQLite for backwards compatibility.
+	DBForeignKeys bool
+
+	// DBMaxOpenConns caps how many connections the pool opens to DBFile.
+	// SQLite handles concurrent readers fine in WAL mode, and writes are
+	// separately serialized in-process, so this mainly bounds reads.
+	DBMaxOpenConns int
+
+	// DBMaxIdleConns caps how many idle connections the pool keeps open
+	// between requests.
+	DBMaxIdleConns int
+
+	// DBConnMaxLifetime closes a pooled connection once it has been open
+	// this long, even if idle; 0 means connections are never recycled by
+	// age.
+	DBConnMaxLifetime time.Duration
+
+	// DBSlowQueryThreshold logs a warning, with bound parameters
+	// redacted, for any repository call taking longer than this. 0
+	// disables slow-query logging; call counts and latency are still
+	// collected either way and exposed through /admin/db/metrics.
+	DBSlowQueryThreshold time.Duration
+
+	// DBWriteRetryDeadline bounds how long a write retries with jittered
+	// backoff after a SQLITE_BUSY/SQLITE_LOCKED error, instead of
+	// bubbling it straight to the caller as a 500. 0 disables retrying.
+	DBWriteRetryDeadline time.Duration
+
+	// DBMaintenanceInterval is how often the background job runs a
+	// quick integrity check and incremental vacuum, surfaced through
+	// logs, /admin/db/metrics and /api/health. 0 disables the job.
+	DBMaintenanceInterval time.Duration
+
+	// DBMaintenanceVacuumPages caps how many freelist pages the job
+	// reclaims per run; 0 reclaims as many as are available.
+	DBMaintenanceVacuumPages int
+
+	// RolloverMode selects what the daily rollover job does with
+	// recurring tasks whose date has passed without completion: "roll"
+	// advances them to their next occurrence, "missed" does the same
+	// but records the occurrence as skipped rather than updated, and
+	// "leave" runs the job but takes no action, only logging what it
+	// found. Empty (the default) disables the job entirely.
+	RolloverMode string
+
+	// WebDir, if set, serves the web UI from this directory on disk
+	// instead of the copy embedded in the binary - useful for editing the
+	// UI without a rebuild. Empty (the default) serves the embedded copy.
+	WebDir string
+
+	// BasePath mounts the API and web UI under this path prefix (e.g.
+	// "/todo") instead of at the root, for hosting behind a reverse proxy
+	// that forwards a subpath. Empty (the default) mounts at the root.
+	// Normalized by Load to have a leading slash and no trailing slash.
+	BasePath string
+
+	// TrustedProxies lists IPs and/or CIDR ranges (e.g. a reverse proxy's
+	// address) allowed to set X-Forwarded-For/X-Forwarded-Proto and have
+	// them trusted for the real client IP and scheme. Empty (the default)
+	// ignores those headers entirely, since trusting them from an
+	// untrusted peer would let it spoof its way past IP-based rate
+	// limiting and login lockouts.
+	TrustedProxies []string
+
+	// AllowedIPs and DeniedIPs are lists of IPs and/or CIDR ranges applied
+	// before authentication: DeniedIPs always blocks a match, and a
+	// non-empty AllowedIPs rejects anything that doesn't match. Both empty
+	// (the default) imposes no restriction. Checked against the resolved
+	// client IP, so a TrustedProxies entry is honored the same as a direct
+	// connection.
+	AllowedIPs []string
+	DeniedIPs  []string
+
+	// Password may be set directly via TODO_PASSWORD or, to keep it out of
+	// the environment and process listing, read from the file named by
+	// TODO_PASSWORD_FILE (e.g. a mounted Docker/Kubernetes secret).
+	// Setting both is an error.
+	Password string
+
+	// JWTSecret, if set, is mixed into the key access tokens are signed
+	// and verified with, on top of the password itself. Read from the
+	// file named by TODO_JWT_SECRET_FILE, the same way as Password, so it
+	// never has to live in the environment either. Empty (the default)
+	// signs tokens from the password alone.
+	JWTSecret string
+
+	// RateLimitRPS and RateLimitBurst configure the /api token-bucket
+	// rate limiter. RateLimitRPS <= 0 disables rate limiting.
+	RateLimitRPS   float64
+	RateLimitBurst float64
+
+	// CORSAllowedOrigins lists origins allowed to call /api cross-origin,
+	// or "*" for any origin. Empty disables CORS entirely.
+	CORSAllowedOrigins []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	CORSMaxAge         int
+
+	// MaxBodyBytes caps the size of API request bodies. <= 0 disables the
+	// limit.
+	MaxBodyBytes int64
+
+	// RequestTimeout bounds how long a single API request may run. <= 0
+	// disables the timeout.
+	RequestTimeout time.Duration
+
+	// ShutdownTimeout bounds how long the server waits for in-flight
+	// requests to drain on SIGINT/SIGTERM/SIGQUIT before forcing them
+	// closed.
+	ShutdownTimeout time.Duration
+
+	// AccessLogSkipStatic excludes the static web UI from access logging,
+	// keeping the log focused on API traffic.
+	AccessLogSkipStatic bool
+
+	// PprofEnabled mounts net/http/pprof on PprofPort, a separate admin
+	// port that is never exposed alongside the public API.
+	PprofEnabled bool
+	PprofPort    int
+
+	// ErrorReporterDSN is the endpoint 5xx errors and panics are reported
+	// to. Empty disables error reporting.
+	ErrorReporterDSN string
+
+	// ListenNetwork is "tcp" (the default, using Port) or "unix" (using
+	// SocketPath), set via TODO_LISTEN=unix:/path/to.sock. A Unix socket
+	// suits deployments where nginx/caddy shares the host and proxies
+	// over the filesystem instead of a loopback port.
+	ListenNetwork string
+	SocketPath    string
+
+	// SocketMode sets the Unix socket's file permissions after binding.
+	// Ignored for TCP. 0 leaves the umask-determined default in place.
+	SocketMode os.FileMode
+
+	// TLSDomain, if set, enables HTTPS: the server terminates TLS on
+	// Port and additionally listens on HTTPRedirectPort to redirect
+	// plain HTTP traffic (and, for a real ACME provider, answer the
+	// HTTP-01 challenge). TLSCacheDir stores the certificate and key
+	// between restarts.
+	TLSDomain        string
+	TLSCacheDir      string
+	HTTPRedirectPort int
+
+	// StaticCacheMaxAge sets the Cache-Control max-age, in seconds, sent
+	// with files under WebDir. <= 0 disables caching headers on them.
+	StaticCacheMaxAge int
+
+	// TLSClientCAFile, if set, enables mutual TLS: the server requires
+	// clients to present a certificate signed by this CA, accepted as an
+	// alternative to password/JWT auth for machine clients. Only takes
+	// effect when TLSDomain is also set.
+	TLSClientCAFile string
+
+	// SlackSigningSecret, if set, enables the /slack/commands endpoint:
+	// requests are rejected unless they carry a valid Slack request
+	// signature computed with this secret. Empty (the default) keeps the
+	// endpoint disabled.
+	SlackSigningSecret string
+
+	// SlackWebhookURL, if set, enables a daily task summary posted to this
+	// Slack incoming webhook at SlackSummaryHour. Empty (the default)
+	// disables the daily summary.
+	SlackWebhookURL string
+
+	// SlackSummaryHour is the local hour (0-23) the daily Slack summary is
+	// posted at. Only takes effect when SlackWebhookURL is also set.
+	SlackSummaryHour int
+
+	// IMAPHost, if set, enables the email-to-task worker: it polls this
+	// "host:port" mailbox over TLS and turns each unseen message into a
+	// task, titled from the subject and commented from the body. Empty
+	// (the default) disables the worker.
+	IMAPHost     string
+	IMAPUsername string
+
+	// IMAPPassword may be set directly via TODO_IMAP_PASSWORD or read
+	// from the file named by TODO_IMAP_PASSWORD_FILE, the same way as
+	// Password. Setting both is an error.
+	IMAPPassword string
+
+	// IMAPMailbox is the folder polled for new mail. Empty defaults to
+	// "INBOX".
+	IMAPMailbox string
+
+	// IMAPPollInterval is how often the mailbox is checked for unseen
+	// messages.
+	IMAPPollInterval time.Duration
+
+	// GoogleClientID and GoogleClientSecret identify the OAuth app used to
+	// refresh access tokens for the Google Tasks sync worker. Both, plus
+	// GoogleRefreshToken, must be set to enable it.
+	GoogleClientID     string
+	GoogleClientSecret string
+
+	// GoogleRefreshToken may be set directly via TODO_GOOGLE_REFRESH_TOKEN
+	// or read from the file named by TODO_GOOGLE_REFRESH_TOKEN_FILE, the
+	// same way as Password. Setting both is an error. It authorizes the
+	// sync worker to act on the user's Google Tasks on its own, without a
+	// browser present, obtained once via Google's OAuth consent flow.
+	GoogleRefreshToken string
+
+	// GoogleTaskListID is the Google Tasks list mirrored to and from,
+	// e.g. "@default" for the user's default list.
+	GoogleTaskListID string
+
+	// GoogleSyncInterval is how often the sync worker pulls changes from
+	// Google Tasks and pushes local ones back.
+	GoogleSyncInterval time.Duration
+
+	// BackupDir, if set, enables the scheduled backup worker: it writes
+	// a timestamped snapshot to this directory every BackupInterval and
+	// prunes old ones under BackupRetentionCount/BackupRetentionAge.
+	// Empty (the default) disables the worker.
+	BackupDir string
+
+	// BackupInterval is how often a scheduled backup is taken.
+	BackupInterval time.Duration
+
+	// BackupRetentionCount keeps at most this many of the newest
+	// scheduled backups. Zero disables count-based pruning.
+	BackupRetentionCount int
+
+	// BackupRetentionAge deletes scheduled backups older than this.
+	// Zero disables age-based pruning.
+	BackupRetentionAge time.Duration
+
+	// BackupS3Endpoint, if set alongside BackupS3Bucket, uploads every
+	// scheduled backup to an S3-compatible bucket after it's written
+	// locally. Endpoint is the bucket's base URL (e.g.
+	// "https://s3.amazonaws.com" or a MinIO URL), not the bucket itself.
+	BackupS3Endpoint string
+	BackupS3Region   string
+	BackupS3Bucket   string
+	// BackupS3Prefix is prepended to each uploaded object's key. Empty
+	// uploads to the bucket root.
+	BackupS3Prefix    string
+	BackupS3AccessKey string
+
+	// BackupS3SecretKey may be set directly via TODO_BACKUP_S3_SECRET_KEY
+	// or read from the file named by TODO_BACKUP_S3_SECRET_KEY_FILE, the
+	// same way as Password. Setting both is an error.
+	BackupS3SecretKey string
+
+	// BackupEncryptionPassphrase, if set, encrypts every scheduled
+	// backup with a key derived from it before it's written to disk or
+	// uploaded. Mutually exclusive with BackupEncryptionAgeRecipient.
+	// May be set directly via TODO_BACKUP_ENCRYPTION_PASSPHRASE or read
+	// from the file named by TODO_BACKUP_ENCRYPTION_PASSPHRASE_FILE, the
+	// same way as Password.
+	BackupEncryptionPassphrase string
+
+	// BackupEncryptionAgeRecipient, if set, encrypts every scheduled
+	// backup to this X25519 public key (an "age1..." string) instead of
+	// a passphrase. See internal/backupcrypto for the caveat that the
+	// resulting file isn't decryptable by the age CLI.
+	BackupEncryptionAgeRecipient string
+
+	// ReminderDefaultLeadDays is how many days before a task's due date
+	// its reminder is sent, for tasks that don't override it via their
+	// own ReminderLeadDays. Only takes effect when at least one
+	// notification channel is configured (SlackWebhookURL or one of the
+	// NotifyXxx settings below).
+	ReminderDefaultLeadDays int
+
+	// ReminderPollInterval is how often the reminder scheduler scans the
+	// task list for reminders coming due.
+	ReminderPollInterval time.Duration
+
+	// NotifyWebhookURL, if set, enables a generic notification channel
+	// that POSTs each event as JSON to this URL. Empty (the default)
+	// disables it.
+	NotifyWebhookURL string
+
+	// NotifyEmailHost and NotifyEmailPort address the SMTP server used
+	// for the email notification channel. Empty NotifyEmailHost (the
+	// default) disables it.
+	NotifyEmailHost string
+	NotifyEmailPort int
+
+	NotifyEmailUsername string
+
+	// NotifyEmailPassword may be set directly via TODO_NOTIFY_EMAIL_PASSWORD
+	// or read from the file named by TODO_NOTIFY_EMAIL_PASSWORD_FILE, the
+	// same way as Password. Setting both is an error.
+	NotifyEmailPassword string
+
+	NotifyEmailFrom string
+	NotifyEmailTo   []string
+
+	// NotifyTelegramBotToken and NotifyTelegramChatID enable the Telegram
+	// notification channel. Empty NotifyTelegramBotToken (the default)
+	// disables it.
+	NotifyTelegramBotToken string
+	NotifyTelegramChatID   string
+
+	// NotifyNtfyURL, if set, enables the ntfy.sh notification channel:
+	// each event's body is POSTed to this topic URL. Empty (the default)
+	// disables it.
+	NotifyNtfyURL string
+
+	// NATSURL, if set, additionally publishes every domain event (see
+	// internal/events) to this NATS server, on subject
+	// NATSSubjectPrefix + "." + event type. Empty (the default) skips
+	// NATS; domain events still fan out in-process regardless.
+	NATSURL string
+
+	// NATSSubjectPrefix namespaces published NATS subjects. Only takes
+	// effect when NATSURL is also set.
+	NATSSubjectPrefix string
+
+	// RedisAddr, if set, enables a cache-aside layer in front of task
+	// reads (see internal/taskcache), backed by this Redis server
+	// (host:port). Empty (the default) skips the cache; reads always
+	// hit the store directly.
+	RedisAddr string
+
+	// CacheTTL bounds how long a cached task list is served without a
+	// matching invalidation event. Only takes effect when RedisAddr is
+	// also set.
+	CacheTTL time.Duration
+
+	// LocalCacheSize, if positive, enables an in-process LRU cache of
+	// this many task listings (see internal/localcache) in front of
+	// reads - a cheaper alternative to RedisAddr for a single-node
+	// deployment. 0 (the default) disables it. Setting both enables
+	// both, with the local cache serving what it can before falling
+	// through to Redis.
+	LocalCacheSize int
+
+	// OutboxPollInterval is how often the outbox dispatcher scans for task
+	// mutation events awaiting delivery.
+	OutboxPollInterval time.Duration
+
+	// RetentionInterval is how often the retention job checks for data
+	// past RetentionReminderHistoryAge/RetentionAuditLogAge and deletes
+	// it (see internal/retention). Backups have their own retention,
+	// enforced on every scheduled run - see BackupRetentionAge.
+	RetentionInterval time.Duration
+
+	// RetentionReminderHistoryAge deletes sent_reminders records - a
+	// task's reminder delivery history - older than this. This app
+	// deletes a completed task outright rather than keeping a
+	// completed-tasks history, so reminder delivery history is the
+	// closest thing it retains to prune. Zero disables it.
+	RetentionReminderHistoryAge time.Duration
+
+	// RetentionAuditLogAge deletes dispatched outbox events - the
+	// audit trail of task mutations - older than this. Pending
+	// (undispatched) events are never pruned regardless of age. Zero
+	// disables it.
+	RetentionAuditLogAge time.Duration
+
+	// TrashRetentionAge permanently removes a task DeleteTask moved to
+	// trash once it's been there longer than this, set in days via
+	// TODO_TRASH_RETENTION_DAYS. Zero disables it, keeping every
+	// deleted task in trash forever.
+	TrashRetentionAge time.Duration
+
+	// Demo replaces whatever is in DBFile with internal/demo's sample
+	// dataset on startup, for screenshots, demo instances and frontend
+	// development against realistic-looking data.
+	Demo bool
+
+	// DemoResetInterval re-seeds the sample dataset on that interval
+	// while running, discarding whatever visitors did to it in the
+	// meantime - e.g. 24h for a public demo that resets nightly. Zero
+	// (the default) seeds once at startup and never again. Only takes
+	// effect when Demo is set.
+	DemoResetInterval time.Duration
+
+	// TasksDefaultLimit is how many rows GET /api/tasks returns when
+	// the request has no ?limit= of its own.
+	TasksDefaultLimit int
+
+	// TasksMaxLimit caps ?limit= on GET /api/tasks; a request asking
+	// for more is clamped down to this rather than rejected.
+	TasksMaxLimit int
+}
+
+// Load reads configuration from an optional --config file and the process
+// environment, falling back to the server's documented defaults where a
+// setting is given by neither. Environment variables override the config
+// file, which overrides the defaults. Load returns an error naming the
+// offending file, variable or value if any of them can't be parsed.
+//
+// Load uses its own FlagSet rather than the global flag.CommandLine so it
+// can be called again later - e.g. to reload settings on SIGHUP - without
+// panicking on a redefined flag. args is normally the serve subcommand's
+// own argument list (os.Args[1:] with any leading subcommand name
+// already stripped by the caller).
+func Load(args []string) (Config, error) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to an optional YAML or TOML config file (see internal/config/file.go for keys); TODO_* environment variables override its values")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		Port:                        defaultPort,
+		DBFile:                      defaultDBFile,
+		DBWALAutocheckpoint:         defaultDBWALAutocheckpoint,
+		DBBusyTimeout:               defaultDBBusyTimeout,
+		DBMaxOpenConns:              defaultDBMaxOpenConns,
+		DBMaxIdleConns:              defaultDBMaxIdleConns,
+		DBConnMaxLifetime:           defaultDBConnMaxLifetime,
+		DBSlowQueryThreshold:        defaultDBSlowQueryThreshold,
+		DBWriteRetryDeadline:        defaultDBWriteRetryDeadline,
+		DBMaintenanceInterval:       defaultDBMaintenanceInterval,
+		DBMaintenanceVacuumPages:    defaultDBMaintenanceVacuumPages,
+		RateLimitRPS:                defaultRateLimitRPS,
+		RateLimitBurst:              defaultRateLimitBurst,
+		CORSAllowedMethods:          splitAndTrim(defaultCORSMethods),
+		CORSAllowedHeaders:          splitAndTrim(defaultCORSHeaders),
+		CORSMaxAge:                  defaultCORSMaxAge,
+		MaxBodyBytes:                defaultMaxBodyBytes,
+		RequestTimeout:              defaultRequestTimeout,
+		ShutdownTimeout:             defaultShutdownTimeout,
+		PprofPort:                   defaultPprofPort,
+		ListenNetwork:               "tcp",
+		TLSCacheDir:                 defaultTLSCacheDir,
+		HTTPRedirectPort:            defaultHTTPRedirectPort,
+		StaticCacheMaxAge:           defaultStaticCacheMaxAge,
+		SlackSummaryHour:            defaultSlackSummaryHour,
+		IMAPMailbox:                 defaultIMAPMailbox,
+		IMAPPollInterval:            defaultIMAPPollInterval,
+		GoogleSyncInterval:          defaultGoogleSyncInterval,
+		BackupInterval:              defaultBackupInterval,
+		BackupRetentionCount:        defaultBackupRetentionCount,
+		ReminderDefaultLeadDays:     defaultReminderLeadDays,
+		ReminderPollInterval:        defaultReminderPollInterval,
+		NotifyEmailPort:             defaultNotifyEmailPort,
+		NATSSubjectPrefix:           defaultNATSSubjectPrefix,
+		OutboxPollInterval:          defaultOutboxPollInterval,
+		CacheTTL:                    defaultCacheTTL,
+		LocalCacheSize:              defaultLocalCacheSize,
+		RetentionInterval:           defaultRetentionInterval,
+		RetentionReminderHistoryAge: defaultRetentionReminderHistoryAge,
+		RetentionAuditLogAge:        defaultRetentionAuditLogAge,
+		TrashRetentionAge:           defaultTrashRetentionDays * 24 * time.Hour,
+		TasksDefaultLimit:           defaultTasksLimit,
+		TasksMaxLimit:               defaultTasksMaxLimit,
+	}
+
+	if *configPath != "" {
+		fc, err := loadConfigFile(*configPath)
+		if err != nil {
+			return Config{}, fmt.Errorf("config file %s: %w", *configPath, err)
+		}
+		if err := applyFileConfig(&cfg, fc); err != nil {
+			return Config{}, fmt.Errorf("config file %s: %w", *configPath, err)
+		}
+	}
+
+	if v := os.Getenv("TODO_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_PORT: invalid port %q: %w", v, err)
+		}
+		cfg.Port = port
+	}
+	if v := os.Getenv("TODO_LISTEN"); v != "" {
+		if path, ok := strings.CutPrefix(v, "unix:"); ok {
+			cfg.ListenNetwork = "unix"
+			cfg.SocketPath = path
+		}
+	}
+	if v := os.Getenv("TODO_SOCKET_MODE"); v != "" {
+		mode, err := strconv.ParseUint(v, 8, 32)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_SOCKET_MODE: invalid mode %q: %w", v, err)
+		}
+		cfg.SocketMode = os.FileMode(mode)
+	}
+	if v := os.Getenv("TODO_DBFILE"); v != "" {
+		cfg.DBFile = v
+	}
+	if v := os.Getenv("TODO_DB_WAL_AUTOCHECKPOINT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("TODO_DB_WAL_AUTOCHECKPOINT: invalid number %q", v)
+		}
+		cfg.DBWALAutocheckpoint = n
+	}
+	if v := os.Getenv("TODO_DB_BUSY_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DB_BUSY_TIMEOUT: invalid duration %q: %w", v, err)
+		}
+		cfg.DBBusyTimeout = timeout
+	}
+	if v := os.Getenv("TODO_DB_FOREIGN_KEYS"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DB_FOREIGN_KEYS: invalid boolean %q: %w", v, err)
+		}
+		cfg.DBForeignKeys = enabled
+	}
+	if v := os.Getenv("TODO_DB_MAX_OPEN_CONNS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("TODO_DB_MAX_OPEN_CONNS: invalid number %q", v)
+		}
+		cfg.DBMaxOpenConns = n
+	}
+	if v := os.Getenv("TODO_DB_MAX_IDLE_CONNS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("TODO_DB_MAX_IDLE_CONNS: invalid number %q", v)
+		}
+		cfg.DBMaxIdleConns = n
+	}
+	if v := os.Getenv("TODO_DB_CONN_MAX_LIFETIME"); v != "" {
+		lifetime, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DB_CONN_MAX_LIFETIME: invalid duration %q: %w", v, err)
+		}
+		cfg.DBConnMaxLifetime = lifetime
+	}
+	if v := os.Getenv("TODO_DB_SLOW_QUERY_THRESHOLD"); v != "" {
+		threshold, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DB_SLOW_QUERY_THRESHOLD: invalid duration %q: %w", v, err)
+		}
+		cfg.DBSlowQueryThreshold = threshold
+	}
+	if v := os.Getenv("TODO_DB_WRITE_RETRY_DEADLINE"); v != "" {
+		deadline, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DB_WRITE_RETRY_DEADLINE: invalid duration %q: %w", v, err)
+		}
+		cfg.DBWriteRetryDeadline = deadline
+	}
+	if v := os.Getenv("TODO_DB_MAINTENANCE_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DB_MAINTENANCE_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.DBMaintenanceInterval = interval
+	}
+	if v := os.Getenv("TODO_DB_MAINTENANCE_VACUUM_PAGES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("TODO_DB_MAINTENANCE_VACUUM_PAGES: invalid number %q", v)
+		}
+		cfg.DBMaintenanceVacuumPages = n
+	}
+	if v := os.Getenv("TODO_ROLLOVER_MODE"); v != "" {
+		switch v {
+		case "roll", "missed", "leave":
+			cfg.RolloverMode = v
+		default:
+			return Config{}, fmt.Errorf("TODO_ROLLOVER_MODE: invalid mode %q (want roll, missed, or leave)", v)
+		}
+	}
+	if v := os.Getenv("TODO_WEB_DIR"); v != "" {
+		cfg.WebDir = v
+	}
+	if v := os.Getenv("TODO_BASE_PATH"); v != "" {
+		cfg.BasePath = normalizeBasePath(v)
+	}
+	if v := os.Getenv("TODO_TRUSTED_PROXIES"); v != "" {
+		cfg.TrustedProxies = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_ALLOWED_IPS"); v != "" {
+		cfg.AllowedIPs = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_DENIED_IPS"); v != "" {
+		cfg.DeniedIPs = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_RATE_LIMIT_RPS"); v != "" {
+		rps, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_RATE_LIMIT_RPS: invalid number %q: %w", v, err)
+		}
+		cfg.RateLimitRPS = rps
+	}
+	if v := os.Getenv("TODO_RATE_LIMIT_BURST"); v != "" {
+		burst, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_RATE_LIMIT_BURST: invalid number %q: %w", v, err)
+		}
+		cfg.RateLimitBurst = burst
+	}
+	if v := os.Getenv("TODO_CORS_ORIGINS"); v != "" {
+		cfg.CORSAllowedOrigins = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_CORS_METHODS"); v != "" {
+		cfg.CORSAllowedMethods = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_CORS_HEADERS"); v != "" {
+		cfg.CORSAllowedHeaders = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_CORS_MAX_AGE"); v != "" {
+		maxAge, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_CORS_MAX_AGE: invalid number %q: %w", v, err)
+		}
+		cfg.CORSMaxAge = maxAge
+	}
+	if v := os.Getenv("TODO_MAX_BODY_BYTES"); v != "" {
+		maxBody, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_MAX_BODY_BYTES: invalid number %q: %w", v, err)
+		}
+		cfg.MaxBodyBytes = maxBody
+	}
+	if v := os.Getenv("TODO_REQUEST_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_REQUEST_TIMEOUT: invalid duration %q: %w", v, err)
+		}
+		cfg.RequestTimeout = timeout
+	}
+	if v := os.Getenv("TODO_SHUTDOWN_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_SHUTDOWN_TIMEOUT: invalid duration %q: %w", v, err)
+		}
+		cfg.ShutdownTimeout = timeout
+	}
+	if v := os.Getenv("TODO_ACCESS_LOG_SKIP_STATIC"); v != "" {
+		skip, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_ACCESS_LOG_SKIP_STATIC: invalid boolean %q: %w", v, err)
+		}
+		cfg.AccessLogSkipStatic = skip
+	}
+	if v := os.Getenv("TODO_PPROF"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_PPROF: invalid boolean %q: %w", v, err)
+		}
+		cfg.PprofEnabled = enabled
+	}
+	if v := os.Getenv("TODO_PPROF_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_PPROF_PORT: invalid port %q: %w", v, err)
+		}
+		cfg.PprofPort = port
+	}
+	if v := os.Getenv("TODO_TLS_DOMAIN"); v != "" {
+		cfg.TLSDomain = v
+	}
+	if v := os.Getenv("TODO_TLS_CACHE_DIR"); v != "" {
+		cfg.TLSCacheDir = v
+	}
+	if v := os.Getenv("TODO_HTTP_REDIRECT_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_HTTP_REDIRECT_PORT: invalid port %q: %w", v, err)
+		}
+		cfg.HTTPRedirectPort = port
+	}
+	if v := os.Getenv("TODO_TLS_CLIENT_CA_FILE"); v != "" {
+		cfg.TLSClientCAFile = v
+	}
+	if v := os.Getenv("TODO_STATIC_CACHE_MAX_AGE"); v != "" {
+		maxAge, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_STATIC_CACHE_MAX_AGE: invalid number %q: %w", v, err)
+		}
+		cfg.StaticCacheMaxAge = maxAge
+	}
+	if v := os.Getenv("TODO_PASSWORD_FILE"); v != "" {
+		password, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_PASSWORD_FILE: %w", err)
+		}
+		cfg.Password = password
+	}
+	if v := os.Getenv("TODO_PASSWORD"); v != "" {
+		if os.Getenv("TODO_PASSWORD_FILE") != "" {
+			return Config{}, fmt.Errorf("TODO_PASSWORD and TODO_PASSWORD_FILE are mutually exclusive")
+		}
+		cfg.Password = v
+	}
+	if v := os.Getenv("TODO_JWT_SECRET_FILE"); v != "" {
+		secret, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_JWT_SECRET_FILE: %w", err)
+		}
+		cfg.JWTSecret = secret
+	}
+	if v := os.Getenv("TODO_ERROR_REPORTER_DSN"); v != "" {
+		cfg.ErrorReporterDSN = v
+	}
+	if v := os.Getenv("TODO_SLACK_SIGNING_SECRET"); v != "" {
+		cfg.SlackSigningSecret = v
+	}
+	if v := os.Getenv("TODO_SLACK_WEBHOOK_URL"); v != "" {
+		cfg.SlackWebhookURL = v
+	}
+	if v := os.Getenv("TODO_SLACK_SUMMARY_HOUR"); v != "" {
+		hour, err := strconv.Atoi(v)
+		if err != nil || hour < 0 || hour > 23 {
+			return Config{}, fmt.Errorf("TODO_SLACK_SUMMARY_HOUR: invalid hour %q (want 0-23)", v)
+		}
+		cfg.SlackSummaryHour = hour
+	}
+	if v := os.Getenv("TODO_IMAP_HOST"); v != "" {
+		cfg.IMAPHost = v
+	}
+	if v := os.Getenv("TODO_IMAP_USERNAME"); v != "" {
+		cfg.IMAPUsername = v
+	}
+	if v := os.Getenv("TODO_IMAP_PASSWORD_FILE"); v != "" {
+		password, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_IMAP_PASSWORD_FILE: %w", err)
+		}
+		cfg.IMAPPassword = password
+	}
+	if v := os.Getenv("TODO_IMAP_PASSWORD"); v != "" {
+		if os.Getenv("TODO_IMAP_PASSWORD_FILE") != "" {
+			return Config{}, fmt.Errorf("TODO_IMAP_PASSWORD and TODO_IMAP_PASSWORD_FILE are mutually exclusive")
+		}
+		cfg.IMAPPassword = v
+	}
+	if v := os.Getenv("TODO_IMAP_MAILBOX"); v != "" {
+		cfg.IMAPMailbox = v
+	}
+	if v := os.Getenv("TODO_IMAP_POLL_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_IMAP_POLL_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.IMAPPollInterval = interval
+	}
+	if v := os.Getenv("TODO_GOOGLE_CLIENT_ID"); v != "" {
+		cfg.GoogleClientID = v
+	}
+	if v := os.Getenv("TODO_GOOGLE_CLIENT_SECRET"); v != "" {
+		cfg.GoogleClientSecret = v
+	}
+	if v := os.Getenv("TODO_GOOGLE_REFRESH_TOKEN_FILE"); v != "" {
+		token, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_GOOGLE_REFRESH_TOKEN_FILE: %w", err)
+		}
+		cfg.GoogleRefreshToken = token
+	}
+	if v := os.Getenv("TODO_GOOGLE_REFRESH_TOKEN"); v != "" {
+		if os.Getenv("TODO_GOOGLE_REFRESH_TOKEN_FILE") != "" {
+			return Config{}, fmt.Errorf("TODO_GOOGLE_REFRESH_TOKEN and TODO_GOOGLE_REFRESH_TOKEN_FILE are mutually exclusive")
+		}
+		cfg.GoogleRefreshToken = v
+	}
+	if v := os.Getenv("TODO_GOOGLE_TASKLIST_ID"); v != "" {
+		cfg.GoogleTaskListID = v
+	}
+	if v := os.Getenv("TODO_GOOGLE_SYNC_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_GOOGLE_SYNC_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.GoogleSyncInterval = interval
+	}
+	if v := os.Getenv("TODO_BACKUP_DIR"); v != "" {
+		cfg.BackupDir = v
+	}
+	if v := os.Getenv("TODO_BACKUP_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_BACKUP_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.BackupInterval = interval
+	}
+	if v := os.Getenv("TODO_BACKUP_RETENTION_COUNT"); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 0 {
+			return Config{}, fmt.Errorf("TODO_BACKUP_RETENTION_COUNT: invalid number %q", v)
+		}
+		cfg.BackupRetentionCount = count
+	}
+	if v := os.Getenv("TODO_BACKUP_RETENTION_AGE"); v != "" {
+		age, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_BACKUP_RETENTION_AGE: invalid duration %q: %w", v, err)
+		}
+		cfg.BackupRetentionAge = age
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_ENDPOINT"); v != "" {
+		cfg.BackupS3Endpoint = v
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_REGION"); v != "" {
+		cfg.BackupS3Region = v
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_BUCKET"); v != "" {
+		cfg.BackupS3Bucket = v
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_PREFIX"); v != "" {
+		cfg.BackupS3Prefix = v
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_ACCESS_KEY"); v != "" {
+		cfg.BackupS3AccessKey = v
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_SECRET_KEY_FILE"); v != "" {
+		key, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_BACKUP_S3_SECRET_KEY_FILE: %w", err)
+		}
+		cfg.BackupS3SecretKey = key
+	}
+	if v := os.Getenv("TODO_BACKUP_S3_SECRET_KEY"); v != "" {
+		if os.Getenv("TODO_BACKUP_S3_SECRET_KEY_FILE") != "" {
+			return Config{}, fmt.Errorf("TODO_BACKUP_S3_SECRET_KEY and TODO_BACKUP_S3_SECRET_KEY_FILE are mutually exclusive")
+		}
+		cfg.BackupS3SecretKey = v
+	}
+	if v := os.Getenv("TODO_BACKUP_ENCRYPTION_PASSPHRASE_FILE"); v != "" {
+		passphrase, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_BACKUP_ENCRYPTION_PASSPHRASE_FILE: %w", err)
+		}
+		cfg.BackupEncryptionPassphrase = passphrase
+	}
+	if v := os.Getenv("TODO_BACKUP_ENCRYPTION_PASSPHRASE"); v != "" {
+		if os.Getenv("TODO_BACKUP_ENCRYPTION_PASSPHRASE_FILE") != "" {
+			return Config{}, fmt.Errorf("TODO_BACKUP_ENCRYPTION_PASSPHRASE and TODO_BACKUP_ENCRYPTION_PASSPHRASE_FILE are mutually exclusive")
+		}
+		cfg.BackupEncryptionPassphrase = v
+	}
+	if v := os.Getenv("TODO_BACKUP_ENCRYPTION_AGE_RECIPIENT"); v != "" {
+		if cfg.BackupEncryptionPassphrase != "" {
+			return Config{}, fmt.Errorf("TODO_BACKUP_ENCRYPTION_AGE_RECIPIENT and TODO_BACKUP_ENCRYPTION_PASSPHRASE are mutually exclusive")
+		}
+		cfg.BackupEncryptionAgeRecipient = v
+	}
+	if v := os.Getenv("TODO_REMINDER_LEAD_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			return Config{}, fmt.Errorf("TODO_REMINDER_LEAD_DAYS: invalid number %q", v)
+		}
+		cfg.ReminderDefaultLeadDays = days
+	}
+	if v := os.Getenv("TODO_REMINDER_POLL_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_REMINDER_POLL_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.ReminderPollInterval = interval
+	}
+	if v := os.Getenv("TODO_NOTIFY_WEBHOOK_URL"); v != "" {
+		cfg.NotifyWebhookURL = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_HOST"); v != "" {
+		cfg.NotifyEmailHost = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_NOTIFY_EMAIL_PORT: invalid port %q: %w", v, err)
+		}
+		cfg.NotifyEmailPort = port
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_USERNAME"); v != "" {
+		cfg.NotifyEmailUsername = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_PASSWORD_FILE"); v != "" {
+		password, err := readSecretFile(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_NOTIFY_EMAIL_PASSWORD_FILE: %w", err)
+		}
+		cfg.NotifyEmailPassword = password
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_PASSWORD"); v != "" {
+		if os.Getenv("TODO_NOTIFY_EMAIL_PASSWORD_FILE") != "" {
+			return Config{}, fmt.Errorf("TODO_NOTIFY_EMAIL_PASSWORD and TODO_NOTIFY_EMAIL_PASSWORD_FILE are mutually exclusive")
+		}
+		cfg.NotifyEmailPassword = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_FROM"); v != "" {
+		cfg.NotifyEmailFrom = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_EMAIL_TO"); v != "" {
+		cfg.NotifyEmailTo = splitAndTrim(v)
+	}
+	if v := os.Getenv("TODO_NOTIFY_TELEGRAM_BOT_TOKEN"); v != "" {
+		cfg.NotifyTelegramBotToken = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_TELEGRAM_CHAT_ID"); v != "" {
+		cfg.NotifyTelegramChatID = v
+	}
+	if v := os.Getenv("TODO_NOTIFY_NTFY_URL"); v != "" {
+		cfg.NotifyNtfyURL = v
+	}
+	if v := os.Getenv("TODO_NATS_URL"); v != "" {
+		cfg.NATSURL = v
+	}
+	if v := os.Getenv("TODO_NATS_SUBJECT_PREFIX"); v != "" {
+		cfg.NATSSubjectPrefix = v
+	}
+	if v := os.Getenv("TODO_OUTBOX_POLL_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_OUTBOX_POLL_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.OutboxPollInterval = interval
+	}
+	if v := os.Getenv("TODO_REDIS_ADDR"); v != "" {
+		cfg.RedisAddr = v
+	}
+	if v := os.Getenv("TODO_CACHE_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_CACHE_TTL: invalid duration %q: %w", v, err)
+		}
+		cfg.CacheTTL = ttl
+	}
+	if v := os.Getenv("TODO_LOCAL_CACHE_SIZE"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 0 {
+			return Config{}, fmt.Errorf("TODO_LOCAL_CACHE_SIZE: invalid size %q", v)
+		}
+		cfg.LocalCacheSize = size
+	}
+	if v := os.Getenv("TODO_RETENTION_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_RETENTION_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.RetentionInterval = interval
+	}
+	if v := os.Getenv("TODO_RETENTION_REMINDER_HISTORY_AGE"); v != "" {
+		age, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_RETENTION_REMINDER_HISTORY_AGE: invalid duration %q: %w", v, err)
+		}
+		cfg.RetentionReminderHistoryAge = age
+	}
+	if v := os.Getenv("TODO_RETENTION_AUDIT_LOG_AGE"); v != "" {
+		age, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_RETENTION_AUDIT_LOG_AGE: invalid duration %q: %w", v, err)
+		}
+		cfg.RetentionAuditLogAge = age
+	}
+	if v := os.Getenv("TODO_TRASH_RETENTION_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			return Config{}, fmt.Errorf("TODO_TRASH_RETENTION_DAYS: invalid number %q", v)
+		}
+		cfg.TrashRetentionAge = time.Duration(days) * 24 * time.Hour
+	}
+	if v := os.Getenv("TODO_DEMO"); v != "" {
+		demo, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DEMO: invalid boolean %q: %w", v, err)
+		}
+		cfg.Demo = demo
+	}
+	if v := os.Getenv("TODO_DEMO_RESET_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("TODO_DEMO_RESET_INTERVAL: invalid duration %q: %w", v, err)
+		}
+		cfg.DemoResetInterval = interval
+	}
+	if v := os.Getenv("TODO_TASKS_DEFAULT_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Config{}, fmt.Errorf("TODO_TASKS_DEFAULT_LIMIT: invalid number %q", v)
+		}
+		cfg.TasksDefaultLimit = n
+	}
+	if v := os.Getenv("TODO_TASKS_MAX_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Config{}, fmt.Errorf("TODO_TASKS_MAX_LIMIT: invalid number %q", v)
+		}
+		cfg.TasksMaxLimit = n
+	}
+	return cfg, nil
+}
+
+// readSecretFile reads a secret from a file - e.g. a mounted
+// Docker/Kubernetes secret - trimming a single trailing newline, which
+// most tools that write these files add.
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+// normalizeBasePath trims a trailing slash and ensures a leading one, so
+// "todo", "/todo" and "/todo/" all mount the app at the same path and
+// callers can safely concatenate BasePath with a leading-slash route
+// pattern.
+func normalizeBasePath(v string) string {
+	v = strings.TrimSuffix(v, "/")
+	if v == "" {
+		return ""
+	}
+	if !strings.HasPrefix(v, "/") {
+		v = "/" + v
+	}
+	return v
+}
+
+// splitAndTrim splits a comma-separated list and trims whitespace from
+// each element, dropping empty ones.
+func splitAndTrim(s string) []string {
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,283 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig mirrors the settings Config accepts from a --config file.
+// Every field is a pointer (or a nil-able slice) so an unset key leaves
+// the corresponding Config field at its default or environment-supplied
+// value rather than zeroing it out. Durations and the socket mode are
+// strings, parsed the same way as their TODO_* environment equivalents.
+type fileConfig struct {
+	Port                   *int     `yaml:"port" toml:"port"`
+	DBFile                 *string  `yaml:"db_file" toml:"db_file"`
+	WebDir                 *string  `yaml:"web_dir" toml:"web_dir"`
+	BasePath               *string  `yaml:"base_path" toml:"base_path"`
+	TrustedProxies         []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+	AllowedIPs             []string `yaml:"allowed_ips" toml:"allowed_ips"`
+	DeniedIPs              []string `yaml:"denied_ips" toml:"denied_ips"`
+	Password               *string  `yaml:"password" toml:"password"`
+	RateLimitRPS           *float64 `yaml:"rate_limit_rps" toml:"rate_limit_rps"`
+	RateLimitBurst         *float64 `yaml:"rate_limit_burst" toml:"rate_limit_burst"`
+	CORSAllowedOrigins     []string `yaml:"cors_allowed_origins" toml:"cors_allowed_origins"`
+	CORSAllowedMethods     []string `yaml:"cors_allowed_methods" toml:"cors_allowed_methods"`
+	CORSAllowedHeaders     []string `yaml:"cors_allowed_headers" toml:"cors_allowed_headers"`
+	CORSMaxAge             *int     `yaml:"cors_max_age" toml:"cors_max_age"`
+	MaxBodyBytes           *int64   `yaml:"max_body_bytes" toml:"max_body_bytes"`
+	RequestTimeout         *string  `yaml:"request_timeout" toml:"request_timeout"`
+	ShutdownTimeout        *string  `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	AccessLogSkipStatic    *bool    `yaml:"access_log_skip_static" toml:"access_log_skip_static"`
+	PprofEnabled           *bool    `yaml:"pprof_enabled" toml:"pprof_enabled"`
+	PprofPort              *int     `yaml:"pprof_port" toml:"pprof_port"`
+	ErrorReporterDSN       *string  `yaml:"error_reporter_dsn" toml:"error_reporter_dsn"`
+	ListenNetwork          *string  `yaml:"listen_network" toml:"listen_network"`
+	SocketPath             *string  `yaml:"socket_path" toml:"socket_path"`
+	SocketMode             *string  `yaml:"socket_mode" toml:"socket_mode"`
+	TLSDomain              *string  `yaml:"tls_domain" toml:"tls_domain"`
+	TLSCacheDir            *string  `yaml:"tls_cache_dir" toml:"tls_cache_dir"`
+	HTTPRedirectPort       *int     `yaml:"http_redirect_port" toml:"http_redirect_port"`
+	TLSClientCAFile        *string  `yaml:"tls_client_ca_file" toml:"tls_client_ca_file"`
+	StaticCacheMaxAge      *int     `yaml:"static_cache_max_age" toml:"static_cache_max_age"`
+	SlackSigningSecret     *string  `yaml:"slack_signing_secret" toml:"slack_signing_secret"`
+	SlackWebhookURL        *string  `yaml:"slack_webhook_url" toml:"slack_webhook_url"`
+	SlackSummaryHour       *int     `yaml:"slack_summary_hour" toml:"slack_summary_hour"`
+	IMAPHost               *string  `yaml:"imap_host" toml:"imap_host"`
+	IMAPUsername           *string  `yaml:"imap_username" toml:"imap_username"`
+	IMAPPassword           *string  `yaml:"imap_password" toml:"imap_password"`
+	IMAPMailbox            *string  `yaml:"imap_mailbox" toml:"imap_mailbox"`
+	IMAPPollInterval       *string  `yaml:"imap_poll_interval" toml:"imap_poll_interval"`
+	ReminderLeadDays       *int     `yaml:"reminder_lead_days" toml:"reminder_lead_days"`
+	ReminderPollInterval   *string  `yaml:"reminder_poll_interval" toml:"reminder_poll_interval"`
+	NotifyWebhookURL       *string  `yaml:"notify_webhook_url" toml:"notify_webhook_url"`
+	NotifyEmailHost        *string  `yaml:"notify_email_host" toml:"notify_email_host"`
+	NotifyEmailPort        *int     `yaml:"notify_email_port" toml:"notify_email_port"`
+	NotifyEmailUsername    *string  `yaml:"notify_email_username" toml:"notify_email_username"`
+	NotifyEmailPassword    *string  `yaml:"notify_email_password" toml:"notify_email_password"`
+	NotifyEmailFrom        *string  `yaml:"notify_email_from" toml:"notify_email_from"`
+	NotifyEmailTo          []string `yaml:"notify_email_to" toml:"notify_email_to"`
+	NotifyTelegramBotToken *string  `yaml:"notify_telegram_bot_token" toml:"notify_telegram_bot_token"`
+	NotifyTelegramChatID   *string  `yaml:"notify_telegram_chat_id" toml:"notify_telegram_chat_id"`
+	NotifyNtfyURL          *string  `yaml:"notify_ntfy_url" toml:"notify_ntfy_url"`
+	NATSURL                *string  `yaml:"nats_url" toml:"nats_url"`
+	NATSSubjectPrefix      *string  `yaml:"nats_subject_prefix" toml:"nats_subject_prefix"`
+	OutboxPollInterval     *string  `yaml:"outbox_poll_interval" toml:"outbox_poll_interval"`
+}
+
+// loadConfigFile reads and parses path as YAML (.yaml/.yml) or TOML
+// (.toml), chosen by its extension.
+func loadConfigFile(path string) (fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileConfig{}, err
+	}
+
+	var fc fileConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &fc); err != nil {
+			return fileConfig{}, fmt.Errorf("parse YAML: %w", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &fc); err != nil {
+			return fileConfig{}, fmt.Errorf("parse TOML: %w", err)
+		}
+	default:
+		return fileConfig{}, fmt.Errorf("unsupported extension %q (expected .yaml, .yml or .toml)", ext)
+	}
+	return fc, nil
+}
+
+// applyFileConfig copies every key fc sets onto cfg, in the same
+// TODO_*-equivalent order Load applies environment variables in.
+func applyFileConfig(cfg *Config, fc fileConfig) error {
+	if fc.Port != nil {
+		cfg.Port = *fc.Port
+	}
+	if fc.ListenNetwork != nil {
+		cfg.ListenNetwork = *fc.ListenNetwork
+	}
+	if fc.SocketPath != nil {
+		cfg.SocketPath = *fc.SocketPath
+	}
+	if fc.SocketMode != nil {
+		mode, err := strconv.ParseUint(*fc.SocketMode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("socket_mode: invalid mode %q: %w", *fc.SocketMode, err)
+		}
+		cfg.SocketMode = os.FileMode(mode)
+	}
+	if fc.DBFile != nil {
+		cfg.DBFile = *fc.DBFile
+	}
+	if fc.WebDir != nil {
+		cfg.WebDir = *fc.WebDir
+	}
+	if fc.BasePath != nil {
+		cfg.BasePath = normalizeBasePath(*fc.BasePath)
+	}
+	if fc.TrustedProxies != nil {
+		cfg.TrustedProxies = fc.TrustedProxies
+	}
+	if fc.AllowedIPs != nil {
+		cfg.AllowedIPs = fc.AllowedIPs
+	}
+	if fc.DeniedIPs != nil {
+		cfg.DeniedIPs = fc.DeniedIPs
+	}
+	if fc.RateLimitRPS != nil {
+		cfg.RateLimitRPS = *fc.RateLimitRPS
+	}
+	if fc.RateLimitBurst != nil {
+		cfg.RateLimitBurst = *fc.RateLimitBurst
+	}
+	if fc.CORSAllowedOrigins != nil {
+		cfg.CORSAllowedOrigins = fc.CORSAllowedOrigins
+	}
+	if fc.CORSAllowedMethods != nil {
+		cfg.CORSAllowedMethods = fc.CORSAllowedMethods
+	}
+	if fc.CORSAllowedHeaders != nil {
+		cfg.CORSAllowedHeaders = fc.CORSAllowedHeaders
+	}
+	if fc.CORSMaxAge != nil {
+		cfg.CORSMaxAge = *fc.CORSMaxAge
+	}
+	if fc.MaxBodyBytes != nil {
+		cfg.MaxBodyBytes = *fc.MaxBodyBytes
+	}
+	if fc.RequestTimeout != nil {
+		d, err := time.ParseDuration(*fc.RequestTimeout)
+		if err != nil {
+			return fmt.Errorf("request_timeout: invalid duration %q: %w", *fc.RequestTimeout, err)
+		}
+		cfg.RequestTimeout = d
+	}
+	if fc.ShutdownTimeout != nil {
+		d, err := time.ParseDuration(*fc.ShutdownTimeout)
+		if err != nil {
+			return fmt.Errorf("shutdown_timeout: invalid duration %q: %w", *fc.ShutdownTimeout, err)
+		}
+		cfg.ShutdownTimeout = d
+	}
+	if fc.AccessLogSkipStatic != nil {
+		cfg.AccessLogSkipStatic = *fc.AccessLogSkipStatic
+	}
+	if fc.PprofEnabled != nil {
+		cfg.PprofEnabled = *fc.PprofEnabled
+	}
+	if fc.PprofPort != nil {
+		cfg.PprofPort = *fc.PprofPort
+	}
+	if fc.TLSDomain != nil {
+		cfg.TLSDomain = *fc.TLSDomain
+	}
+	if fc.TLSCacheDir != nil {
+		cfg.TLSCacheDir = *fc.TLSCacheDir
+	}
+	if fc.HTTPRedirectPort != nil {
+		cfg.HTTPRedirectPort = *fc.HTTPRedirectPort
+	}
+	if fc.TLSClientCAFile != nil {
+		cfg.TLSClientCAFile = *fc.TLSClientCAFile
+	}
+	if fc.StaticCacheMaxAge != nil {
+		cfg.StaticCacheMaxAge = *fc.StaticCacheMaxAge
+	}
+	if fc.Password != nil {
+		cfg.Password = *fc.Password
+	}
+	if fc.ErrorReporterDSN != nil {
+		cfg.ErrorReporterDSN = *fc.ErrorReporterDSN
+	}
+	if fc.SlackSigningSecret != nil {
+		cfg.SlackSigningSecret = *fc.SlackSigningSecret
+	}
+	if fc.SlackWebhookURL != nil {
+		cfg.SlackWebhookURL = *fc.SlackWebhookURL
+	}
+	if fc.SlackSummaryHour != nil {
+		cfg.SlackSummaryHour = *fc.SlackSummaryHour
+	}
+	if fc.IMAPHost != nil {
+		cfg.IMAPHost = *fc.IMAPHost
+	}
+	if fc.IMAPUsername != nil {
+		cfg.IMAPUsername = *fc.IMAPUsername
+	}
+	if fc.IMAPPassword != nil {
+		cfg.IMAPPassword = *fc.IMAPPassword
+	}
+	if fc.IMAPMailbox != nil {
+		cfg.IMAPMailbox = *fc.IMAPMailbox
+	}
+	if fc.IMAPPollInterval != nil {
+		d, err := time.ParseDuration(*fc.IMAPPollInterval)
+		if err != nil {
+			return fmt.Errorf("imap_poll_interval: invalid duration %q: %w", *fc.IMAPPollInterval, err)
+		}
+		cfg.IMAPPollInterval = d
+	}
+	if fc.ReminderLeadDays != nil {
+		cfg.ReminderDefaultLeadDays = *fc.ReminderLeadDays
+	}
+	if fc.ReminderPollInterval != nil {
+		d, err := time.ParseDuration(*fc.ReminderPollInterval)
+		if err != nil {
+			return fmt.Errorf("reminder_poll_interval: invalid duration %q: %w", *fc.ReminderPollInterval, err)
+		}
+		cfg.ReminderPollInterval = d
+	}
+	if fc.NotifyWebhookURL != nil {
+		cfg.NotifyWebhookURL = *fc.NotifyWebhookURL
+	}
+	if fc.NotifyEmailHost != nil {
+		cfg.NotifyEmailHost = *fc.NotifyEmailHost
+	}
+	if fc.NotifyEmailPort != nil {
+		cfg.NotifyEmailPort = *fc.NotifyEmailPort
+	}
+	if fc.NotifyEmailUsername != nil {
+		cfg.NotifyEmailUsername = *fc.NotifyEmailUsername
+	}
+	if fc.NotifyEmailPassword != nil {
+		cfg.NotifyEmailPassword = *fc.NotifyEmailPassword
+	}
+	if fc.NotifyEmailFrom != nil {
+		cfg.NotifyEmailFrom = *fc.NotifyEmailFrom
+	}
+	if fc.NotifyEmailTo != nil {
+		cfg.NotifyEmailTo = fc.NotifyEmailTo
+	}
+	if fc.NotifyTelegramBotToken != nil {
+		cfg.NotifyTelegramBotToken = *fc.NotifyTelegramBotToken
+	}
+	if fc.NotifyTelegramChatID != nil {
+		cfg.NotifyTelegramChatID = *fc.NotifyTelegramChatID
+	}
+	if fc.NotifyNtfyURL != nil {
+		cfg.NotifyNtfyURL = *fc.NotifyNtfyURL
+	}
+	if fc.NATSURL != nil {
+		cfg.NATSURL = *fc.NATSURL
+	}
+	if fc.NATSSubjectPrefix != nil {
+		cfg.NATSSubjectPrefix = *fc.NATSSubjectPrefix
+	}
+	if fc.OutboxPollInterval != nil {
+		d, err := time.ParseDuration(*fc.OutboxPollInterval)
+		if err != nil {
+			return fmt.Errorf("outbox_poll_interval: invalid duration %q: %w", *fc.OutboxPollInterval, err)
+		}
+		cfg.OutboxPollInterval = d
+	}
+	return nil
+}
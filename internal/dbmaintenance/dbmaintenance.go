@@ -0,0 +1,113 @@
+// Package dbmaintenance implements a background worker that periodically
+// runs a cheap integrity check and incremental vacuum against the
+// database, recording the outcome so it can be surfaced through logs,
+// repository metrics, and the health endpoint without every caller
+// re-running the check itself.
+package dbmaintenance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Result is the outcome of the most recently completed check.
+type Result struct {
+	OK      bool
+	Message string
+	// CheckedAt is the zero time until the first check has run.
+	CheckedAt time.Time
+}
+
+// Worker runs PRAGMA quick_check and an incremental vacuum against
+// maintainer on Interval, recording the result of each run so Last can
+// report it without touching the database itself.
+type Worker struct {
+	maintainer  storage.Maintainer
+	interval    time.Duration
+	vacuumPages int
+
+	mu   sync.Mutex
+	last Result
+}
+
+// New returns a Worker checking maintainer every interval, reclaiming up
+// to vacuumPages pages per run (0 reclaims as many as are available).
+func New(maintainer storage.Maintainer, interval time.Duration, vacuumPages int) *Worker {
+	return &Worker{maintainer: maintainer, interval: interval, vacuumPages: vacuumPages}
+}
+
+// Run checks the database every w.interval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check runs a quick_check followed by an incremental vacuum, recording
+// the combined result. A failed or non-OK check is logged and stops
+// short of vacuuming, since vacuuming a database already known to be
+// corrupt could make recovery harder rather than easier.
+func (w *Worker) check(ctx context.Context) {
+	report, err := w.maintainer.QuickCheck(ctx)
+	if err != nil {
+		w.record(Result{OK: false, Message: err.Error(), CheckedAt: time.Now()})
+		log.Printf("dbmaintenance: quick check: %v", err)
+		return
+	}
+	if !report.OK {
+		w.record(Result{OK: false, Message: report.Message, CheckedAt: time.Now()})
+		log.Printf("dbmaintenance: quick check failed: %s", report.Message)
+		return
+	}
+
+	vacuumReport, err := w.maintainer.IncrementalVacuum(ctx, w.vacuumPages)
+	if err != nil {
+		w.record(Result{OK: false, Message: err.Error(), CheckedAt: time.Now()})
+		log.Printf("dbmaintenance: incremental vacuum: %v", err)
+		return
+	}
+
+	w.record(Result{
+		OK:        true,
+		Message:   fmt.Sprintf("quick_check: %s; %s", report.Message, vacuumReport.Message),
+		CheckedAt: time.Now(),
+	})
+}
+
+func (w *Worker) record(r Result) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = r
+}
+
+// Last returns the outcome of the most recently completed check. Before
+// the first check has run, it reports OK with a zero CheckedAt, so a
+// health endpoint backed by a freshly started Worker doesn't report
+// unhealthy before it's had a chance to run.
+func (w *Worker) Last() Result {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.last.CheckedAt.IsZero() {
+		return Result{OK: true, Message: "no check run yet"}
+	}
+	return w.last
+}
+
+// Healthy reports the most recent check's outcome in the shape
+// api.HealthChecker expects.
+func (w *Worker) Healthy() (bool, string) {
+	r := w.Last()
+	return r.OK, r.Message
+}
@@ -0,0 +1,123 @@
+// Package demo seeds the database with a realistic sample of tasks for
+// screenshots, demo instances and frontend development (see TODO_DEMO
+// in internal/config), optionally re-seeding it on a schedule so a
+// public demo instance doesn't accumulate visitors' edits.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Worker wipes store's data and replaces it with SampleTasks, the job
+// scheduler's entry point for a periodic reset.
+type Worker struct {
+	store storage.Store
+	wiper storage.DataWiper
+}
+
+// New returns a Worker that seeds store, wiping its existing data
+// through wiper first.
+func New(store storage.Store, wiper storage.DataWiper) *Worker {
+	return &Worker{store: store, wiper: wiper}
+}
+
+// RunOnce wipes every task (and everything that references one) and
+// inserts a fresh copy of SampleTasks in its place.
+func (w *Worker) RunOnce(ctx context.Context) error {
+	if _, err := w.wiper.WipeAllData(ctx); err != nil {
+		return fmt.Errorf("wipe existing data: %w", err)
+	}
+	if _, err := w.store.ImportTasks(ctx, SampleTasks(time.Now())); err != nil {
+		return fmt.Errorf("import sample tasks: %w", err)
+	}
+	return nil
+}
+
+// SampleTasks returns a realistic mix of recurring and one-off tasks
+// dated relative to now, so a demo instance always looks freshly used
+// regardless of when it was last seeded: some tasks overdue, some due
+// today, some upcoming.
+func SampleTasks(now time.Time) []storage.Task {
+	today := nextdate.Format(now)
+	yesterday := nextdate.Format(now.AddDate(0, 0, -1))
+	tomorrow := nextdate.Format(now.AddDate(0, 0, 1))
+	nextWeek := nextdate.Format(now.AddDate(0, 0, 7))
+
+	return []storage.Task{
+		{
+			Date:    today,
+			Title:   "Ежедневный стендап",
+			Comment: "Синхронизация с командой в 10:00",
+			Repeat:  "d 1",
+			Time:    "10:00",
+			Color:   storage.ColorBlue,
+		},
+		{
+			Date:    today,
+			Title:   "Проверить почту",
+			Comment: "",
+			Repeat:  "d 1",
+			Color:   storage.ColorGray,
+		},
+		{
+			Date:    nextdate.Format(nextMonday(now)),
+			Title:   "Ретроспектива спринта",
+			Comment: "Что получилось, что нет, что улучшить",
+			Repeat:  "w 1",
+			Color:   storage.ColorPurple,
+		},
+		{
+			Date:    yesterday,
+			Title:   "Ответить клиенту по контракту",
+			Comment: "Просрочено — нужно сделать в первую очередь",
+			Color:   storage.ColorRed,
+		},
+		{
+			Date:    today,
+			Title:   "Подготовить демо для инвесторов",
+			Comment: "Слайды + живой прогон приложения",
+			Color:   storage.ColorOrange,
+		},
+		{
+			Date:    tomorrow,
+			Title:   "Ревью пул-реквестов",
+			Comment: "",
+			Color:   storage.ColorYellow,
+		},
+		{
+			Date:    nextWeek,
+			Title:   "Оплатить счета за хостинг",
+			Comment: "",
+			Repeat:  "m 1",
+			Color:   storage.ColorGreen,
+		},
+		{
+			Date:    nextdate.Format(now.AddDate(0, 0, 3)),
+			Title:   "Купить подарок на день рождения",
+			Comment: "",
+		},
+		{
+			Date:    nextdate.Format(now.AddDate(0, 0, 14)),
+			Title:   "Продлить страховку",
+			Comment: "",
+			Color:   storage.ColorGray,
+		},
+	}
+}
+
+// nextMonday returns the next Monday on or after now, so the weekly
+// sample task always lands on a plausible day regardless of when the
+// demo data is (re)seeded.
+func nextMonday(now time.Time) time.Time {
+	for {
+		now = now.AddDate(0, 0, 1)
+		if now.Weekday() == time.Monday {
+			return now
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package errorreporter forwards server-side errors to an external
+// collector (e.g. a self-hosted error tracker), so operators learn about
+// 5xx responses and panics without tailing logs.
+package errorreporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Reporter posts error events to a configured DSN endpoint as JSON. A nil
+// *Reporter is valid and Report becomes a no-op, matching how the rest of
+// the server treats optional integrations.
+type Reporter struct {
+	dsn    string
+	client *http.Client
+}
+
+// New returns a Reporter that posts to dsn, or nil if dsn is empty,
+// disabling error reporting.
+func New(dsn string) *Reporter {
+	if dsn == "" {
+		return nil
+	}
+	return &Reporter{dsn: dsn, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+type event struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Report sends err (and the request ID that produced it, if any) to the
+// configured DSN in the background. Delivery failures are not retried or
+// surfaced - error reporting must never affect the request it reports on.
+func (r *Reporter) Report(err error, requestID string) {
+	if r == nil || err == nil {
+		return
+	}
+	go r.send(event{Error: err.Error(), RequestID: requestID})
+}
+
+func (r *Reporter) send(e event) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, r.dsn, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,143 @@
+// Package events implements an in-process domain-event bus for the
+// scheduler: handlers publish an Event after a task mutation succeeds,
+// subscribers (e.g. the notification fan-out wired up in cmd_serve.go)
+// react to it without the request path waiting on them, and the event is
+// optionally mirrored to NATS for external consumers.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Type identifies the kind of change an Event describes.
+type Type string
+
+const (
+	TaskCreated   Type = "task.created"
+	TaskUpdated   Type = "task.updated"
+	TaskCompleted Type = "task.completed"
+	TaskDeleted   Type = "task.deleted"
+	// TaskSkipped is published when a recurring task is advanced to its
+	// next occurrence without being marked done - see
+	// api.handleSkipTask, distinct from TaskCompleted.
+	TaskSkipped Type = "task.skipped"
+)
+
+// Event describes a single change to a task.
+type Event struct {
+	Type Type         `json:"type"`
+	Task storage.Task `json:"task"`
+}
+
+// Handler reacts to a published Event. It runs off the request path (see
+// Bus.Publish), so it may take its time without slowing down the caller
+// that published the event.
+type Handler func(ctx context.Context, event Event)
+
+// Bus fans a published Event out to every in-process Handler subscribed
+// to it and, when configured with a NATS server, publishes it there too.
+// A nil *Bus is valid and both Publish and Subscribe become no-ops,
+// matching how the rest of the server treats optional integrations (see
+// internal/errorreporter.Reporter).
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[int]Handler
+	nextID      int
+
+	nats    *nats.Conn
+	subject string
+}
+
+// New returns a Bus. If natsURL is set, it also connects to that NATS
+// server and publishes every Event to subjectPrefix + "." + event type
+// (e.g. "tasks.task.created"). An empty natsURL skips NATS entirely.
+func New(natsURL, subjectPrefix string) (*Bus, error) {
+	b := &Bus{subject: subjectPrefix, subscribers: make(map[int]Handler)}
+	if natsURL == "" {
+		return b, nil
+	}
+
+	nc, err := nats.Connect(natsURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+	b.nats = nc
+	return b, nil
+}
+
+// Subscribe registers h to run on every future Publish call, returning a
+// function that unsubscribes it - e.g. an SSE handler subscribing for the
+// lifetime of one client connection and unsubscribing when it closes.
+func (b *Bus) Subscribe(h Handler) (unsubscribe func()) {
+	if b == nil {
+		return func() {}
+	}
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = h
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish notifies every subscriber and, if configured, NATS, in a
+// background goroutine so the caller - typically an HTTP handler - isn't
+// held up by a slow notifier or a stalled NATS connection.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := make([]Handler, 0, len(b.subscribers))
+	for _, h := range b.subscribers {
+		subscribers = append(subscribers, h)
+	}
+	b.mu.RUnlock()
+
+	go func() {
+		// The request that triggered this event may already be gone by
+		// the time subscribers run, so give them a fresh context rather
+		// than one tied to the (possibly canceled) request.
+		bgCtx := context.Background()
+		for _, h := range subscribers {
+			h(bgCtx, event)
+		}
+		if b.nats != nil {
+			if err := b.publishNATS(event); err != nil {
+				log.Printf("events: publish to nats: %v", err)
+			}
+		}
+	}()
+}
+
+func (b *Bus) publishNATS(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	return b.nats.Publish(b.subject+"."+string(event.Type), data)
+}
+
+// Close releases the NATS connection, if one was opened. A nil Bus or one
+// without NATS configured is a no-op.
+func (b *Bus) Close() error {
+	if b == nil || b.nats == nil {
+		return nil
+	}
+	b.nats.Close()
+	return nil
+}
@@ -0,0 +1,241 @@
+// Package googlesync mirrors the scheduler's tasks to a Google Tasks
+// list and pulls changes back, for users who also manage their tasks
+// from Google's own apps.
+//
+// Conflict rule: on each sync, Google's side is applied first - a task
+// created, edited or deleted there overwrites the local copy - and only
+// then are local tasks that Google didn't just touch pushed back up. So
+// if the same task changed on both sides between two sync runs, Google's
+// edit wins and the local edit is lost. This keeps the rule simple and
+// predictable at the cost of favoring one side; there's no reliable way
+// to tell which edit happened "first" across two separately-clocked
+// systems without a lot more bookkeeping than a personal task list
+// warrants.
+package googlesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/googletasks"
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// tasksLimit caps how many local tasks a single sync pass reads, well
+// above any realistic personal task list.
+const tasksLimit = 1 << 16
+
+// googleDueLayout is the RFC 3339 timestamp format the Tasks API expects
+// for a task's due date; the time-of-day component is ignored by Google,
+// so it's always set to midnight UTC.
+const googleDueLayout = "2006-01-02T15:04:05.000Z"
+
+// Worker mirrors tasks between store and a Google Tasks list on an
+// interval.
+type Worker struct {
+	store    storage.Store
+	mappings storage.GoogleSyncStore
+	client   *googletasks.Client
+	interval time.Duration
+}
+
+// New returns a Worker syncing store's tasks with client's list every
+// interval. interval must be positive.
+func New(store storage.Store, mappings storage.GoogleSyncStore, client *googletasks.Client, interval time.Duration) (*Worker, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("googlesync: sync interval must be positive")
+	}
+	return &Worker{store: store, mappings: mappings, client: client, interval: interval}, nil
+}
+
+// Run syncs on w.interval until ctx is done. A failed sync is logged and
+// retried on the next tick rather than stopping the worker.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if err := w.syncOnce(ctx); err != nil {
+			log.Printf("googlesync: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncOnce pulls every change from Google, applies it locally, then
+// pushes back any local task Google's side didn't just touch.
+func (w *Worker) syncOnce(ctx context.Context) error {
+	remoteTasks, err := w.client.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list google tasks: %w", err)
+	}
+	mappings, err := w.mappings.GoogleMappings(ctx)
+	if err != nil {
+		return fmt.Errorf("list mappings: %w", err)
+	}
+
+	byTask := make(map[int64]storage.GoogleTaskMapping, len(mappings))
+	byGoogleID := make(map[string]storage.GoogleTaskMapping, len(mappings))
+	for _, m := range mappings {
+		byTask[m.TaskID] = m
+		byGoogleID[m.GoogleTaskID] = m
+	}
+
+	// touchedByGoogle records which local tasks were just created,
+	// updated or deleted from Google's side this pass, so the push step
+	// below doesn't immediately overwrite them with stale local content.
+	touchedByGoogle := map[int64]bool{}
+
+	for _, rt := range remoteTasks {
+		m, mapped := byGoogleID[rt.ID]
+
+		if rt.Deleted {
+			if !mapped {
+				continue
+			}
+			if err := w.store.DeleteTask(ctx, m.TaskID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return fmt.Errorf("delete local task %d: %w", m.TaskID, err)
+			}
+			if err := w.mappings.DeleteGoogleMapping(ctx, m.TaskID); err != nil {
+				return fmt.Errorf("delete mapping for task %d: %w", m.TaskID, err)
+			}
+			touchedByGoogle[m.TaskID] = true
+			continue
+		}
+
+		if !mapped {
+			t := taskFromGoogle(rt)
+			id, err := w.store.AddTask(ctx, t)
+			if err != nil {
+				return fmt.Errorf("add task from google: %w", err)
+			}
+			if err := w.mappings.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{TaskID: id, GoogleTaskID: rt.ID, GoogleUpdated: rt.Updated}); err != nil {
+				return fmt.Errorf("save mapping for task %d: %w", id, err)
+			}
+			touchedByGoogle[id] = true
+			continue
+		}
+
+		if rt.Updated == m.GoogleUpdated {
+			// Unchanged on Google's side since last sync; leave it for
+			// the push step to consider.
+			continue
+		}
+		t := taskFromGoogle(rt)
+		t.ID = m.TaskID
+		if err := w.store.UpdateTask(ctx, t); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				// The local task was deleted since we last saw it, but
+				// Google still has it; recreate it locally rather than
+				// silently dropping Google's edit.
+				id, err := w.store.AddTask(ctx, t)
+				if err != nil {
+					return fmt.Errorf("recreate task %d from google: %w", m.TaskID, err)
+				}
+				if err := w.mappings.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{TaskID: id, GoogleTaskID: rt.ID, GoogleUpdated: rt.Updated}); err != nil {
+					return fmt.Errorf("save mapping for task %d: %w", id, err)
+				}
+				touchedByGoogle[id] = true
+				continue
+			}
+			return fmt.Errorf("update local task %d: %w", m.TaskID, err)
+		}
+		if err := w.mappings.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{TaskID: m.TaskID, GoogleTaskID: rt.ID, GoogleUpdated: rt.Updated}); err != nil {
+			return fmt.Errorf("save mapping for task %d: %w", m.TaskID, err)
+		}
+		touchedByGoogle[m.TaskID] = true
+	}
+
+	localTasks, err := w.store.Tasks(ctx, "", tasksLimit, false, "", nil, "")
+	if err != nil {
+		return fmt.Errorf("list local tasks: %w", err)
+	}
+	seen := make(map[int64]bool, len(localTasks))
+	for _, t := range localTasks {
+		seen[t.ID] = true
+		if touchedByGoogle[t.ID] {
+			continue
+		}
+
+		m, mapped := byTask[t.ID]
+		if !mapped {
+			created, err := w.client.Insert(ctx, taskToGoogle(t))
+			if err != nil {
+				return fmt.Errorf("insert google task for local task %d: %w", t.ID, err)
+			}
+			if err := w.mappings.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{TaskID: t.ID, GoogleTaskID: created.ID, GoogleUpdated: created.Updated}); err != nil {
+				return fmt.Errorf("save mapping for task %d: %w", t.ID, err)
+			}
+			continue
+		}
+
+		gt := taskToGoogle(t)
+		gt.ID = m.GoogleTaskID
+		updated, err := w.client.Update(ctx, gt)
+		if err != nil {
+			return fmt.Errorf("update google task for local task %d: %w", t.ID, err)
+		}
+		if err := w.mappings.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{TaskID: t.ID, GoogleTaskID: m.GoogleTaskID, GoogleUpdated: updated.Updated}); err != nil {
+			return fmt.Errorf("save mapping for task %d: %w", t.ID, err)
+		}
+	}
+
+	// Local tasks deleted since the last sync still have a mapping but no
+	// longer appear in localTasks; mirror the deletion to Google.
+	for _, m := range mappings {
+		if seen[m.TaskID] || touchedByGoogle[m.TaskID] {
+			continue
+		}
+		if err := w.client.Delete(ctx, m.GoogleTaskID); err != nil {
+			return fmt.Errorf("delete google task for local task %d: %w", m.TaskID, err)
+		}
+		if err := w.mappings.DeleteGoogleMapping(ctx, m.TaskID); err != nil {
+			return fmt.Errorf("delete mapping for task %d: %w", m.TaskID, err)
+		}
+	}
+
+	return nil
+}
+
+// taskFromGoogle converts a Google Tasks entry into a local task. Google
+// Tasks has no repetition concept, so Repeat is always empty; a
+// completed Google task has no local equivalent (a completed local task
+// is deleted, see storage.Store.CompleteTask), so it's mirrored in as a
+// pending task rather than dropped.
+func taskFromGoogle(t googletasks.Task) storage.Task {
+	date := nextdate.Format(time.Now())
+	if t.Due != "" {
+		if due, err := time.Parse(googleDueLayout, t.Due); err == nil {
+			date = nextdate.Format(due)
+		}
+	}
+	return storage.Task{
+		Date:    date,
+		Title:   t.Title,
+		Comment: t.Notes,
+	}
+}
+
+// taskToGoogle converts a local task into a Google Tasks entry. Status
+// is always "needsAction": a completed local task is deleted rather than
+// marked done (see storage.Store.CompleteTask), so a task mirrored to
+// Google is by definition still pending.
+func taskToGoogle(t storage.Task) googletasks.Task {
+	due := ""
+	if d, err := nextdate.Parse(t.Date); err == nil {
+		due = d.Format(googleDueLayout)
+	}
+	return googletasks.Task{
+		Title:  t.Title,
+		Notes:  t.Comment,
+		Due:    due,
+		Status: "needsAction",
+	}
+}
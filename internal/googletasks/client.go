@@ -0,0 +1,246 @@
+// Package googletasks is a minimal client for the Google Tasks REST API
+// (https://developers.google.com/tasks/reference/rest), covering just
+// what the sync worker in internal/googlesync needs: listing, creating,
+// updating and deleting tasks in a single list. It authenticates with a
+// long-lived OAuth refresh token rather than depending on a full OAuth2
+// client library, matching how the rest of this codebase talks to
+// external HTTP APIs (see internal/notify) with net/http alone.
+package googletasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// tokenURL is Google's OAuth2 token endpoint, used to exchange a refresh
+// token for a short-lived access token.
+const tokenURL = "https://oauth2.googleapis.com/token"
+
+// apiBase is the Google Tasks API's base URL.
+const apiBase = "https://tasks.googleapis.com/tasks/v1"
+
+// Task is a single Google Tasks entry, trimmed to the fields the sync
+// worker reads or writes.
+type Task struct {
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title"`
+	Notes   string `json:"notes,omitempty"`
+	Due     string `json:"due,omitempty"`    // RFC 3339, time-of-day ignored by the API
+	Status  string `json:"status,omitempty"` // "needsAction" or "completed"
+	Updated string `json:"updated,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// Client talks to the Google Tasks API for a single task list, using
+// OAuth credentials to keep its access token fresh.
+type Client struct {
+	httpClient *http.Client
+	tasklistID string
+
+	clientID     string
+	clientSecret string
+	refreshToken string
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewClient returns a Client authenticating with clientID, clientSecret
+// and refreshToken, operating on the task list identified by tasklistID
+// (e.g. "@default" for the account's default list).
+func NewClient(clientID, clientSecret, refreshToken, tasklistID string) *Client {
+	return &Client{
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+		tasklistID:   tasklistID,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		refreshToken: refreshToken,
+	}
+}
+
+// List returns every task in the list, including recently deleted and
+// hidden (completed) ones, so the sync worker can detect deletions and
+// completions made on the Google side.
+func (c *Client) List(ctx context.Context) ([]Task, error) {
+	var tasks []Task
+	pageToken := ""
+	for {
+		q := url.Values{
+			"showDeleted": {"true"},
+			"showHidden":  {"true"},
+			"maxResults":  {"100"},
+			"fields":      {"nextPageToken,items(id,title,notes,due,status,updated,deleted)"},
+		}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		var page struct {
+			Items         []Task `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := c.do(ctx, http.MethodGet, "/lists/"+url.PathEscape(c.tasklistID)+"/tasks?"+q.Encode(), nil, &page); err != nil {
+			return nil, fmt.Errorf("list tasks: %w", err)
+		}
+		tasks = append(tasks, page.Items...)
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return tasks, nil
+}
+
+// Insert creates t in the list and returns the created task, with its
+// assigned ID and Updated timestamp filled in.
+func (c *Client) Insert(ctx context.Context, t Task) (Task, error) {
+	var created Task
+	err := c.do(ctx, http.MethodPost, "/lists/"+url.PathEscape(c.tasklistID)+"/tasks", t, &created)
+	if err != nil {
+		return Task{}, fmt.Errorf("insert task: %w", err)
+	}
+	return created, nil
+}
+
+// Update overwrites the task identified by t.ID with t's other fields,
+// returning the updated task with a fresh Updated timestamp.
+func (c *Client) Update(ctx context.Context, t Task) (Task, error) {
+	if t.ID == "" {
+		return Task{}, fmt.Errorf("update task: missing id")
+	}
+	var updated Task
+	path := "/lists/" + url.PathEscape(c.tasklistID) + "/tasks/" + url.PathEscape(t.ID)
+	if err := c.do(ctx, http.MethodPut, path, t, &updated); err != nil {
+		return Task{}, fmt.Errorf("update task %s: %w", t.ID, err)
+	}
+	return updated, nil
+}
+
+// Delete removes the task identified by id from the list. Deleting a
+// task that's already gone is not an error.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	path := "/lists/" + url.PathEscape(c.tasklistID) + "/tasks/" + url.PathEscape(id)
+	err := c.do(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete task %s: %w", id, err)
+	}
+	return nil
+}
+
+// do issues an authenticated request against the Tasks API, encoding
+// body as JSON when non-nil and decoding the response into out when
+// non-nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	token, err := c.accessTokenFor(ctx)
+	if err != nil {
+		return fmt.Errorf("get access token: %w", err)
+	}
+
+	var bodyReader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		bodyReader = bytes.NewReader(encoded)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+
+	fullURL := path
+	if len(path) == 0 || path[0] == '/' {
+		fullURL = apiBase + path
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return &apiError{status: resp.StatusCode}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// apiError reports a non-2xx Tasks API response by status code.
+type apiError struct {
+	status int
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.status)
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.status == http.StatusNotFound
+}
+
+// accessTokenFor returns a valid access token, refreshing it against
+// Google's token endpoint if the cached one is missing or about to
+// expire.
+func (c *Client) accessTokenFor(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.expiresAt) {
+		return c.accessToken, nil
+	}
+
+	form := url.Values{
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"refresh_token": {c.refreshToken},
+		"grant_type":    {"refresh_token"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewReader([]byte(form.Encode())))
+	if err != nil {
+		return "", fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("refresh token: unexpected status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+
+	c.accessToken = token.AccessToken
+	// Refresh a little early so a request never races an expiry that
+	// happens mid-flight.
+	c.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - 30*time.Second)
+	return c.accessToken, nil
+}
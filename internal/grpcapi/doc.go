@@ -0,0 +1,19 @@
+// Package grpcapi will serve TaskService (see proto/task/v1/task.proto)
+// on a second port alongside the HTTP API in cmd_serve.go, backed by the
+// same storage.Store the HTTP handlers use, for automation clients and
+// the future mobile app that want typed, streaming-capable access.
+//
+// It's currently just the proto contract: generating the Go stubs needs
+// protoc plus protoc-gen-go and protoc-gen-go-grpc, none of which are
+// reachable from this environment (only the Go module proxy is - no
+// apt, no github.com release downloads), so wiring a real grpc.Server
+// here would mean hand-rolling the generated marshalling code, which
+// isn't how this repo generates anything else. Once run somewhere with
+// protoc available:
+//
+//	protoc --go_out=. --go-grpc_out=. proto/task/v1/task.proto
+//
+// generate taskv1, then this package implements taskv1.TaskServiceServer
+// by delegating to storage.Store, the same way internal/api's handlers
+// do, and cmd_serve.go registers it on a new TODO_GRPC_PORT listener.
+package grpcapi
@@ -0,0 +1,196 @@
+// Package jobs is a small registry for the server's periodic background
+// work - backup, database maintenance, the recurring-task rollover, and
+// the like - so each one doesn't have to hand-roll its own ticker loop,
+// overlap guard and failure bookkeeping. A feature that needs finer
+// control than "run this on an interval" (reminder's poll-and-send
+// pipeline, backup's own health-check integration) can still keep its
+// dedicated Worker type; this package is for the simpler common case.
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Job is one unit of periodic work.
+type Job struct {
+	// Name identifies the job in logs, persisted run history and Stats.
+	Name string
+	// Interval is how often Run is attempted, and the default used to
+	// compute the next due time from the last run if NextDue is nil. A
+	// run that's still in-flight when the next tick arrives is skipped
+	// rather than piling up concurrently - see Scheduler.Run.
+	Interval time.Duration
+	// NextDue, given the time of the job's last run (the zero time if it
+	// has never run), computes when it should next run. Nil defaults to
+	// last.Add(Interval), for a job that just runs periodically; a job
+	// tied to a wall-clock schedule instead - e.g. once a day at local
+	// midnight - sets this to compute that instead of using Interval.
+	NextDue func(last time.Time) time.Time
+	// Run performs one execution of the job.
+	Run func(ctx context.Context) error
+}
+
+// nextDue computes when j should next run, given the time of its last
+// run (the zero time if it has never run).
+func (j Job) nextDue(last time.Time) time.Time {
+	if j.NextDue != nil {
+		return j.NextDue(last)
+	}
+	return last.Add(j.Interval)
+}
+
+// Stats summarizes a registered job's run history since the process
+// started (Runs, Failures) and, if a RunStore was configured, since
+// before that too (LastRun, LastOK, LastMessage persist across
+// restarts).
+type Stats struct {
+	Name     string `json:"name"`
+	Runs     int64  `json:"runs"`
+	Failures int64  `json:"failures"`
+	// LastRun is the zero time until Name has run at least once.
+	LastRun      time.Time     `json:"last_run"`
+	LastOK       bool          `json:"last_ok"`
+	LastMessage  string        `json:"last_message,omitempty"`
+	LastDuration time.Duration `json:"last_duration_ns"`
+}
+
+// entry is the scheduler's bookkeeping for one registered Job.
+type entry struct {
+	job Job
+
+	// running guards against Run's ticker firing again before the
+	// previous execution has returned, for a Job whose Run can
+	// occasionally take longer than its Interval.
+	running sync.Mutex
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Scheduler runs a set of registered Jobs, each on its own interval,
+// tracking per-job run counts, failures and (if runStore is non-nil)
+// persisting the last outcome so a restart doesn't immediately re-run a
+// job whose interval hasn't actually elapsed yet.
+type Scheduler struct {
+	runStore storage.JobRuns
+	entries  []*entry
+}
+
+// New returns a Scheduler. runStore may be nil, in which case last-run
+// state doesn't survive a process restart but everything else - locking,
+// intervals, in-memory Stats - still works.
+func New(runStore storage.JobRuns) *Scheduler {
+	return &Scheduler{runStore: runStore}
+}
+
+// Register adds job to the scheduler. It has no effect until Run starts.
+func (s *Scheduler) Register(job Job) {
+	s.entries = append(s.entries, &entry{job: job, stats: Stats{Name: job.Name}})
+}
+
+// Run starts every registered job on its own goroutine, ticking at its
+// configured Interval, until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			s.runJob(ctx, e)
+		}(e)
+	}
+	wg.Wait()
+}
+
+// runJob waits until e is next due - immediately, unless a persisted
+// last run says otherwise - then executes it, rearming for its next due
+// time after each run, until ctx is done.
+func (s *Scheduler) runJob(ctx context.Context, e *entry) {
+	var last time.Time
+	if s.runStore != nil {
+		if run, ok, err := s.runStore.LastJobRun(ctx, e.job.Name); err == nil && ok {
+			last = run.RanAt
+		}
+	}
+
+	wait := time.Until(e.job.nextDue(last))
+	if wait < 0 {
+		wait = 0
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		s.execute(ctx, e)
+
+		e.mu.Lock()
+		last = e.stats.LastRun
+		e.mu.Unlock()
+		if wait = time.Until(e.job.nextDue(last)); wait < 0 {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// execute runs e.job.Run once, recording its outcome in Stats and, if
+// configured, in runStore. If the previous execution of e is still
+// running - Run took longer than Interval - this tick is skipped rather
+// than run concurrently with it.
+func (s *Scheduler) execute(ctx context.Context, e *entry) {
+	if !e.running.TryLock() {
+		log.Printf("jobs: %s still running, skipping this tick", e.job.Name)
+		return
+	}
+	defer e.running.Unlock()
+
+	start := time.Now()
+	err := e.job.Run(ctx)
+	duration := time.Since(start)
+
+	message := "ok"
+	if err != nil {
+		message = err.Error()
+		log.Printf("jobs: %s: %v", e.job.Name, err)
+	}
+
+	e.mu.Lock()
+	e.stats.Runs++
+	if err != nil {
+		e.stats.Failures++
+	}
+	e.stats.LastRun = start
+	e.stats.LastOK = err == nil
+	e.stats.LastMessage = message
+	e.stats.LastDuration = duration
+	e.mu.Unlock()
+
+	if s.runStore != nil {
+		run := storage.JobRun{Name: e.job.Name, RanAt: start, OK: err == nil, Message: message}
+		if err := s.runStore.RecordJobRun(ctx, run); err != nil {
+			log.Printf("jobs: %s: record run: %v", e.job.Name, err)
+		}
+	}
+}
+
+// Stats reports the current run history for every registered job,
+// sorted by registration order.
+func (s *Scheduler) Stats() []Stats {
+	stats := make([]Stats, len(s.entries))
+	for i, e := range s.entries {
+		e.mu.Lock()
+		stats[i] = e.stats
+		e.mu.Unlock()
+	}
+	return stats
+}
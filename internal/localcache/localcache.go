@@ -0,0 +1,105 @@
+// Package localcache implements an in-process LRU cache of task
+// listings in front of a storage.Store, for single-node deployments
+// that want most of taskcache's read-avoidance without operating a
+// Redis server.
+package localcache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Store wraps a storage.Store, caching Tasks and Task reads in an
+// in-process LRU of the given size, cleared on every task mutation
+// event. Every other storage.Store method passes straight through to
+// the wrapped store. The underlying lru.Cache is already safe for
+// concurrent use, so Store needs no locking of its own.
+type Store struct {
+	storage.Store
+	cache       *lru.Cache[string, any]
+	unsubscribe func()
+
+	hits, misses atomic.Int64
+}
+
+// New wraps inner with an LRU cache of size entries, subscribing to bus
+// so a task mutation clears it. size must be positive. Call Close to
+// unsubscribe; it does not close inner.
+func New(inner storage.Store, size int, bus *events.Bus) (*Store, error) {
+	cache, err := lru.New[string, any](size)
+	if err != nil {
+		return nil, fmt.Errorf("new lru cache: %w", err)
+	}
+	s := &Store{Store: inner, cache: cache}
+	s.unsubscribe = bus.Subscribe(func(ctx context.Context, event events.Event) {
+		s.cache.Purge()
+	})
+	return s, nil
+}
+
+// Tasks serves search from the cache when present, falling back to and
+// then repopulating from the wrapped store on a miss.
+func (s *Store) Tasks(ctx context.Context, search string, limit int, starredOnly bool, color storage.TaskColor, recurring *bool, repeatPrefix string) ([]storage.Task, error) {
+	key := fmt.Sprintf("list:%d:%s:%t:%s:%s:%s", limit, search, starredOnly, color, recurringKey(recurring), repeatPrefix)
+
+	if cached, ok := s.cache.Get(key); ok {
+		s.hits.Add(1)
+		return cached.([]storage.Task), nil
+	}
+	s.misses.Add(1)
+
+	tasks, err := s.Store.Tasks(ctx, search, limit, starredOnly, color, recurring, repeatPrefix)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Add(key, tasks)
+	return tasks, nil
+}
+
+// recurringKey renders recurring for use in a cache key: dereferencing it
+// rather than formatting the pointer itself, whose address is meaningless
+// across calls.
+func recurringKey(recurring *bool) string {
+	if recurring == nil {
+		return ""
+	}
+	return fmt.Sprintf("%t", *recurring)
+}
+
+// Task serves id from the cache when present, falling back to and then
+// repopulating from the wrapped store on a miss.
+func (s *Store) Task(ctx context.Context, id int64) (storage.Task, error) {
+	key := fmt.Sprintf("task:%d", id)
+
+	if cached, ok := s.cache.Get(key); ok {
+		s.hits.Add(1)
+		return cached.(storage.Task), nil
+	}
+	s.misses.Add(1)
+
+	t, err := s.Store.Task(ctx, id)
+	if err != nil {
+		return storage.Task{}, err
+	}
+	s.cache.Add(key, t)
+	return t, nil
+}
+
+// Stats reports the cache's cumulative hit and miss counts since it was
+// created.
+func (s *Store) Stats() (hits, misses int64) {
+	return s.hits.Load(), s.misses.Load()
+}
+
+// Close unsubscribes from the event bus. It does not close the wrapped
+// store, which callers typically close separately.
+func (s *Store) Close() error {
+	s.unsubscribe()
+	return nil
+}
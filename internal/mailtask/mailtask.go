@@ -0,0 +1,187 @@
+// Package mailtask turns emails in an IMAP mailbox into scheduler tasks:
+// the subject becomes the title, the body becomes the comment, and an
+// optional "+20250601"-style date tag in the subject sets the task's due
+// date instead of today.
+package mailtask
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// dateTag matches a scheduler date tag (e.g. "+20250601") anywhere in a
+// subject line, the same "+YYYYMMDD" shorthand the web UI's repeat field
+// uses for a one-off date.
+var dateTag = regexp.MustCompile(`\+(\d{8})`)
+
+// Config holds the settings a Worker needs to poll a mailbox.
+type Config struct {
+	// Host is the IMAP server's "host:port" address, dialed over TLS.
+	Host string
+
+	Username string
+	Password string
+
+	// Mailbox is the folder polled for new mail. Empty defaults to
+	// "INBOX".
+	Mailbox string
+
+	// PollInterval is how often the mailbox is checked for unseen
+	// messages. Empty (0) is invalid; New rejects it.
+	PollInterval time.Duration
+}
+
+// Worker polls a Config's mailbox and turns each unseen message into a
+// task via store.
+type Worker struct {
+	cfg   Config
+	store storage.Store
+}
+
+// New returns a Worker polling cfg's mailbox and adding tasks to store.
+// cfg.Host, cfg.Username and cfg.Password must be set.
+func New(cfg Config, store storage.Store) (*Worker, error) {
+	if cfg.Host == "" || cfg.Username == "" || cfg.Password == "" {
+		return nil, fmt.Errorf("mailtask: host, username and password are required")
+	}
+	if cfg.Mailbox == "" {
+		cfg.Mailbox = "INBOX"
+	}
+	if cfg.PollInterval <= 0 {
+		return nil, fmt.Errorf("mailtask: poll interval must be positive")
+	}
+	return &Worker{cfg: cfg, store: store}, nil
+}
+
+// Run polls the mailbox on cfg.PollInterval until ctx is done. Errors
+// polling or connecting are logged and retried on the next tick rather
+// than stopping the worker.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := w.poll(ctx); err != nil {
+			log.Printf("mailtask: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll connects, converts every unseen message in the configured mailbox
+// into a task, marks each one seen, and disconnects.
+func (w *Worker) poll(ctx context.Context) error {
+	c, err := client.DialTLS(w.cfg.Host, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", w.cfg.Host, err)
+	}
+	defer c.Logout()
+
+	if err := c.Login(w.cfg.Username, w.cfg.Password); err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+	if _, err := c.Select(w.cfg.Mailbox, false); err != nil {
+		return fmt.Errorf("select %s: %w", w.cfg.Mailbox, err)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	ids, err := c.Search(criteria)
+	if err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(ids...)
+	section := &imap.BodySectionName{}
+	messages := make(chan *imap.Message, len(ids))
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Fetch(seqset, []imap.FetchItem{section.FetchItem()}, messages)
+	}()
+
+	for msg := range messages {
+		if err := w.addTask(ctx, msg, section); err != nil {
+			log.Printf("mailtask: skipping message: %v", err)
+		}
+	}
+	if err := <-done; err != nil {
+		return fmt.Errorf("fetch: %w", err)
+	}
+
+	item := imap.FormatFlagsOp(imap.AddFlags, true)
+	flags := []interface{}{imap.SeenFlag}
+	if err := c.Store(seqset, item, flags, nil); err != nil {
+		return fmt.Errorf("mark seen: %w", err)
+	}
+	return nil
+}
+
+// addTask parses msg's subject and body into a task and adds it to the
+// store.
+func (w *Worker) addTask(ctx context.Context, msg *imap.Message, section *imap.BodySectionName) error {
+	r := msg.GetBody(section)
+	if r == nil {
+		return fmt.Errorf("no body returned for message")
+	}
+	m, err := mail.ReadMessage(r)
+	if err != nil {
+		return fmt.Errorf("parse message: %w", err)
+	}
+	subject := m.Header.Get("Subject")
+
+	title, date := parseSubject(subject)
+	if title == "" {
+		return fmt.Errorf("empty subject")
+	}
+
+	var comment strings.Builder
+	if _, err := io.Copy(&comment, m.Body); err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+
+	t := storage.Task{
+		Date:    date,
+		Title:   title,
+		Comment: strings.TrimSpace(comment.String()),
+	}
+	if _, err := w.store.AddTask(ctx, t); err != nil {
+		return fmt.Errorf("add task: %w", err)
+	}
+	return nil
+}
+
+// parseSubject strips a "+YYYYMMDD" date tag out of subject, returning
+// the remaining title and, if the tag was present and valid, its date in
+// the scheduler's canonical format. Without a valid tag, date is today.
+func parseSubject(subject string) (title, date string) {
+	date = nextdate.Format(time.Now())
+	loc := dateTag.FindStringSubmatchIndex(subject)
+	if loc == nil {
+		return strings.TrimSpace(subject), date
+	}
+	tag := subject[loc[2]:loc[3]]
+	if _, err := nextdate.Parse(tag); err == nil {
+		date = tag
+	}
+	title = strings.TrimSpace(subject[:loc[0]] + subject[loc[1]:])
+	return title, date
+}
@@ -0,0 +1,33 @@
+// Package markdown renders user-supplied Markdown (e.g. a task comment)
+// to sanitized HTML safe to embed directly in the web UI.
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+// policy is a UGC (user-generated content) sanitization policy: it keeps
+// the tags and attributes Markdown commonly produces (headings,
+// emphasis, lists, links, code blocks) while stripping anything that
+// could carry script content, such as <script> tags, inline event
+// handlers or javascript: URLs.
+var policy = bluemonday.UGCPolicy()
+
+// Render converts raw Markdown to sanitized HTML. It never returns an
+// error: goldmark's default parser accepts any input, treating anything
+// it doesn't recognize as plain text, and sanitization only removes
+// content, never fails.
+func Render(raw string) string {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(raw), &buf); err != nil {
+		// goldmark.Convert only errors if a renderer step returns one;
+		// the default pipeline never does, so this is unreachable in
+		// practice - fall back to sanitizing the raw text rather than
+		// panicking or dropping the comment.
+		return policy.Sanitize(raw)
+	}
+	return policy.Sanitize(buf.String())
+}
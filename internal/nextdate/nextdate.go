@@ -0,0 +1,379 @@
+// Package nextdate implements the repetition rules used by the scheduler:
+// the "repeat" field on a task determines when it should reappear after
+// being marked done.
+package nextdate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the scheduler's canonical date format.
+const DateLayout = "20060102"
+
+// Parse parses a date string in the scheduler's canonical YYYYMMDD format.
+func Parse(date string) (time.Time, error) {
+	return time.Parse(DateLayout, date)
+}
+
+// Format renders a time.Time back into the scheduler's canonical format.
+func Format(t time.Time) string {
+	return t.Format(DateLayout)
+}
+
+// Next computes the next occurrence of date according to repeat, always
+// strictly after now. It advances at least once, even when date is
+// already after now.
+func Next(now time.Time, date, repeat string) (string, error) {
+	if repeat == "" {
+		return "", fmt.Errorf("repeat rule is empty")
+	}
+
+	start, err := Parse(date)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", date, err)
+	}
+
+	fields := strings.Fields(repeat)
+	switch fields[0] {
+	case "y":
+		if len(fields) != 1 {
+			return "", fmt.Errorf("unsupported repeat rule %q", repeat)
+		}
+		return Format(nextYearly(start, now)), nil
+
+	case "d":
+		if len(fields) != 2 {
+			return "", fmt.Errorf("repeat rule %q is missing the interval", repeat)
+		}
+		days, err := strconv.Atoi(fields[1])
+		if err != nil || days < 1 || days > 400 {
+			return "", fmt.Errorf("repeat rule %q: interval must be between 1 and 400 days", repeat)
+		}
+		return Format(nextDaily(start, now, days)), nil
+
+	case "w":
+		if len(fields) != 2 {
+			return "", fmt.Errorf("repeat rule %q is missing the weekdays", repeat)
+		}
+		weekdays, err := parseWeekdays(fields[1])
+		if err != nil {
+			return "", fmt.Errorf("repeat rule %q: %w", repeat, err)
+		}
+		return Format(nextWeekly(start, now, weekdays)), nil
+
+	case "m":
+		if len(fields) < 2 || len(fields) > 3 {
+			return "", fmt.Errorf("unsupported repeat rule %q", repeat)
+		}
+		days, err := parseMonthDays(fields[1])
+		if err != nil {
+			return "", fmt.Errorf("repeat rule %q: %w", repeat, err)
+		}
+		var months map[int]bool
+		if len(fields) == 3 {
+			months, err = parseMonths(fields[2])
+			if err != nil {
+				return "", fmt.Errorf("repeat rule %q: %w", repeat, err)
+			}
+		}
+		return Format(nextMonthly(start, now, days, months)), nil
+
+	default:
+		return "", fmt.Errorf("unsupported repeat rule %q", repeat)
+	}
+}
+
+// isoWeekdayCodes maps the scheduler's 1 (Monday) - 7 (Sunday) weekday
+// numbering to RFC 5545 BYDAY codes.
+var isoWeekdayCodes = [...]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// RRule translates repeat into the value of an RFC 5545 RRULE property
+// (without the "RRULE:" prefix), for embedding a task's repetition into an
+// iCalendar feed. It recognizes the same rules as Next and rejects
+// anything Next would.
+func RRule(repeat string) (string, error) {
+	fields := strings.Fields(repeat)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("repeat rule is empty")
+	}
+
+	switch fields[0] {
+	case "y":
+		if len(fields) != 1 {
+			return "", fmt.Errorf("unsupported repeat rule %q", repeat)
+		}
+		return "FREQ=YEARLY", nil
+
+	case "d":
+		if len(fields) != 2 {
+			return "", fmt.Errorf("repeat rule %q is missing the interval", repeat)
+		}
+		days, err := strconv.Atoi(fields[1])
+		if err != nil || days < 1 || days > 400 {
+			return "", fmt.Errorf("repeat rule %q: interval must be between 1 and 400 days", repeat)
+		}
+		return fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", days), nil
+
+	case "w":
+		if len(fields) != 2 {
+			return "", fmt.Errorf("repeat rule %q is missing the weekdays", repeat)
+		}
+		weekdays, err := parseWeekdays(fields[1])
+		if err != nil {
+			return "", fmt.Errorf("repeat rule %q: %w", repeat, err)
+		}
+		days := make([]string, 0, len(weekdays))
+		for _, v := range sortedKeys(weekdays) {
+			days = append(days, isoWeekdayCodes[v-1])
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ","), nil
+
+	case "m":
+		if len(fields) < 2 || len(fields) > 3 {
+			return "", fmt.Errorf("unsupported repeat rule %q", repeat)
+		}
+		monthDays, err := parseMonthDays(fields[1])
+		if err != nil {
+			return "", fmt.Errorf("repeat rule %q: %w", repeat, err)
+		}
+		rule := "FREQ=MONTHLY;BYMONTHDAY=" + joinInts(sortedKeys(monthDays))
+		if len(fields) == 3 {
+			months, err := parseMonths(fields[2])
+			if err != nil {
+				return "", fmt.Errorf("repeat rule %q: %w", repeat, err)
+			}
+			rule += ";BYMONTH=" + joinInts(sortedKeys(months))
+		}
+		return rule, nil
+
+	default:
+		return "", fmt.Errorf("unsupported repeat rule %q", repeat)
+	}
+}
+
+// FromRRule translates an RFC 5545 RRULE value (without the "RRULE:"
+// prefix) into the scheduler's repeat DSL, the inverse of RRule. It only
+// recognizes the shapes RRule produces and rejects anything else - COUNT,
+// UNTIL, an INTERVAL on a non-daily FREQ, and so on - since the
+// scheduler's rules have no equivalent for them.
+func FromRRule(rrule string) (string, error) {
+	params := map[string]string{}
+	for _, part := range strings.Split(rrule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		params[strings.ToUpper(kv[0])] = kv[1]
+	}
+
+	if !onlyKeys(params, "FREQ") && !onlyKeys(params, "FREQ", "INTERVAL") &&
+		!onlyKeys(params, "FREQ", "BYDAY") && !onlyKeys(params, "FREQ", "BYMONTHDAY") &&
+		!onlyKeys(params, "FREQ", "BYMONTHDAY", "BYMONTH") {
+		return "", fmt.Errorf("unsupported RRULE %q", rrule)
+	}
+
+	switch params["FREQ"] {
+	case "YEARLY":
+		if len(params) != 1 {
+			return "", fmt.Errorf("unsupported RRULE %q", rrule)
+		}
+		return "y", nil
+
+	case "DAILY":
+		interval := "1"
+		if v, ok := params["INTERVAL"]; ok {
+			interval = v
+		}
+		if _, err := strconv.Atoi(interval); err != nil {
+			return "", fmt.Errorf("unsupported RRULE %q", rrule)
+		}
+		return "d " + interval, nil
+
+	case "WEEKLY":
+		byday, ok := params["BYDAY"]
+		if !ok {
+			return "", fmt.Errorf("unsupported RRULE %q", rrule)
+		}
+		weekdays := make([]int, 0)
+		for _, code := range strings.Split(byday, ",") {
+			wd, err := weekdayFromCode(code)
+			if err != nil {
+				return "", fmt.Errorf("unsupported RRULE %q: %w", rrule, err)
+			}
+			weekdays = append(weekdays, wd)
+		}
+		sort.Ints(weekdays)
+		return "w " + joinInts(weekdays), nil
+
+	case "MONTHLY":
+		monthDays, ok := params["BYMONTHDAY"]
+		if !ok {
+			return "", fmt.Errorf("unsupported RRULE %q", rrule)
+		}
+		if _, err := parseMonthDays(monthDays); err != nil {
+			return "", fmt.Errorf("unsupported RRULE %q: %w", rrule, err)
+		}
+		rule := "m " + monthDays
+		if months, ok := params["BYMONTH"]; ok {
+			if _, err := parseMonths(months); err != nil {
+				return "", fmt.Errorf("unsupported RRULE %q: %w", rrule, err)
+			}
+			rule += " " + months
+		}
+		return rule, nil
+
+	default:
+		return "", fmt.Errorf("unsupported RRULE %q", rrule)
+	}
+}
+
+// onlyKeys reports whether params has exactly the given keys, no more and
+// no fewer.
+func onlyKeys(params map[string]string, keys ...string) bool {
+	if len(params) != len(keys) {
+		return false
+	}
+	for _, k := range keys {
+		if _, ok := params[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// weekdayFromCode is the inverse of isoWeekdayCodes.
+func weekdayFromCode(code string) (int, error) {
+	for i, c := range isoWeekdayCodes {
+		if c == code {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("weekday code %q is not recognized", code)
+}
+
+// sortedKeys returns m's keys in ascending order, so output built from a
+// map (iteration order of which Go leaves unspecified) is deterministic.
+func sortedKeys(m map[int]bool) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = strconv.Itoa(v)
+	}
+	return strings.Join(strs, ",")
+}
+
+func nextYearly(start, now time.Time) time.Time {
+	next := start
+	for {
+		next = next.AddDate(1, 0, 0)
+		if next.After(now) {
+			return next
+		}
+	}
+}
+
+func nextDaily(start, now time.Time, days int) time.Time {
+	next := start
+	for {
+		next = next.AddDate(0, 0, days)
+		if next.After(now) {
+			return next
+		}
+	}
+}
+
+func nextWeekly(start, now time.Time, weekdays map[int]bool) time.Time {
+	next := start
+	for {
+		next = next.AddDate(0, 0, 1)
+		if weekdays[isoWeekday(next)] && next.After(now) {
+			return next
+		}
+	}
+}
+
+func nextMonthly(start, now time.Time, days, months map[int]bool) time.Time {
+	next := start
+	for {
+		next = next.AddDate(0, 0, 1)
+		if len(months) > 0 && !months[int(next.Month())] {
+			continue
+		}
+		if matchesMonthDay(next, days) && next.After(now) {
+			return next
+		}
+	}
+}
+
+func isoWeekday(t time.Time) int {
+	if wd := int(t.Weekday()); wd != 0 {
+		return wd
+	}
+	return 7
+}
+
+func matchesMonthDay(t time.Time, days map[int]bool) bool {
+	if days[t.Day()] {
+		return true
+	}
+	last := lastDayOfMonth(t)
+	if days[-1] && t.Day() == last {
+		return true
+	}
+	if days[-2] && t.Day() == last-1 {
+		return true
+	}
+	return false
+}
+
+func lastDayOfMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+func parseWeekdays(s string) (map[int]bool, error) {
+	weekdays := map[int]bool{}
+	for _, tok := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(tok)
+		if err != nil || v < 1 || v > 7 {
+			return nil, fmt.Errorf("weekday %q must be between 1 and 7", tok)
+		}
+		weekdays[v] = true
+	}
+	return weekdays, nil
+}
+
+func parseMonthDays(s string) (map[int]bool, error) {
+	days := map[int]bool{}
+	for _, tok := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(tok)
+		if err != nil || v == 0 || v < -2 || v > 31 {
+			return nil, fmt.Errorf("day %q must be between 1 and 31, or -1/-2", tok)
+		}
+		days[v] = true
+	}
+	return days, nil
+}
+
+func parseMonths(s string) (map[int]bool, error) {
+	months := map[int]bool{}
+	for _, tok := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(tok)
+		if err != nil || v < 1 || v > 12 {
+			return nil, fmt.Errorf("month %q must be between 1 and 12", tok)
+		}
+		months[v] = true
+	}
+	return months, nil
+}
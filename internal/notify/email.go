@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Email sends an Event as a plain-text email over SMTP. A nil *Email is
+// valid and Send becomes a no-op, matching how the rest of the server
+// treats optional integrations (see internal/errorreporter.Reporter).
+type Email struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+// NewEmail returns an Email delivering through the SMTP server at
+// host:port, authenticating as username/password when username is set,
+// or nil if host, from or to is empty, disabling the channel.
+func NewEmail(host string, port int, username, password, from string, to []string) *Email {
+	if host == "" || from == "" || len(to) == 0 {
+		return nil
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &Email{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from, to: to}
+}
+
+// Send ignores ctx: net/smtp has no context-aware API.
+func (e *Email) Send(_ context.Context, event Event) error {
+	if e == nil {
+		return nil
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		e.from, strings.Join(e.to, ", "), event.Title, event.Body)
+	if err := smtp.SendMail(e.addr, e.auth, e.from, e.to, []byte(msg)); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
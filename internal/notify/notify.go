@@ -0,0 +1,37 @@
+// Package notify defines a pluggable notification-channel abstraction.
+// Each channel (webhook, email, Telegram, ntfy.sh, ...) implements
+// Notifier; callers combine any number of configured channels into a
+// single fan-out Notifier with Multi.
+package notify
+
+import "context"
+
+// Event is a single notification to deliver: a short Title and a longer
+// Body, generic enough for every backend to render in its own way.
+type Event struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Notifier delivers an Event through some external channel.
+type Notifier interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// Multi fans an Event out to every Notifier it holds. A nil or empty
+// Multi is a valid no-op Notifier, so callers can always pass one
+// without checking whether any channel is actually configured.
+type Multi []Notifier
+
+// Send calls Send on every Notifier in m, continuing past individual
+// failures so one broken channel doesn't block the others, and returns
+// the first error encountered, if any.
+func (m Multi) Send(ctx context.Context, event Event) error {
+	var firstErr error
+	for _, n := range m {
+		if err := n.Send(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
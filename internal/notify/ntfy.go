@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Ntfy publishes an Event to a ntfy.sh (or self-hosted ntfy) topic. A nil
+// *Ntfy is valid and Send becomes a no-op, matching how the rest of the
+// server treats optional integrations (see internal/errorreporter.Reporter).
+type Ntfy struct {
+	url    string
+	client *http.Client
+}
+
+// NewNtfy returns a Ntfy notifier publishing to the topic URL, or nil if
+// url is empty, disabling the channel.
+func NewNtfy(url string) *Ntfy {
+	if url == "" {
+		return nil
+	}
+	return &Ntfy{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (n *Ntfy) Send(ctx context.Context, event Event) error {
+	if n == nil {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, strings.NewReader(event.Body))
+	if err != nil {
+		return fmt.Errorf("build ntfy request: %w", err)
+	}
+	// ntfy percent-decodes header values that look percent-encoded, which
+	// is the only reliable way to carry a non-ASCII title: raw UTF-8
+	// bytes in an HTTP header are outside the HTTP spec and get mangled
+	// by some servers and proxies.
+	req.Header.Set("Title", url.QueryEscape(event.Title))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post ntfy event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("post ntfy event: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
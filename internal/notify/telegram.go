@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// telegramAPI is the Telegram Bot API base URL, a var so tests can point
+// it at a local server.
+var telegramAPI = "https://api.telegram.org"
+
+// Telegram sends an Event as a message from a Telegram bot to a single
+// chat. A nil *Telegram is valid and Send becomes a no-op, matching how
+// the rest of the server treats optional integrations (see
+// internal/errorreporter.Reporter).
+type Telegram struct {
+	botToken string
+	chatID   string
+	client   *http.Client
+}
+
+// NewTelegram returns a Telegram notifier sending through botToken to
+// chatID, or nil if either is empty, disabling the channel.
+func NewTelegram(botToken, chatID string) *Telegram {
+	if botToken == "" || chatID == "" {
+		return nil
+	}
+	return &Telegram{botToken: botToken, chatID: chatID, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+type telegramMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+func (t *Telegram) Send(ctx context.Context, event Event) error {
+	if t == nil {
+		return nil
+	}
+
+	text := event.Title
+	if event.Body != "" {
+		text += "\n" + event.Body
+	}
+	body, err := json.Marshal(telegramMessage{ChatID: t.chatID, Text: text})
+	if err != nil {
+		return fmt.Errorf("encode telegram message: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPI, t.botToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("post telegram message: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
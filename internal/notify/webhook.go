@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook posts an Event as a JSON object ({"title":"...","body":"..."})
+// to an arbitrary HTTP endpoint. A nil *Webhook is valid and Send becomes
+// a no-op, matching how the rest of the server treats optional
+// integrations (see internal/errorreporter.Reporter).
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns a Webhook posting to url, or nil if url is empty,
+// disabling the channel.
+func NewWebhook(url string) *Webhook {
+	if url == "" {
+		return nil
+	}
+	return &Webhook{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (w *Webhook) Send(ctx context.Context, event Event) error {
+	if w == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode webhook event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("post webhook event: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
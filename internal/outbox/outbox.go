@@ -0,0 +1,71 @@
+// Package outbox delivers domain events recorded in the transactional
+// outbox (see internal/storage.OutboxStore) to the in-process event bus,
+// so a crash between a task mutation and its notification can't lose the
+// event: on restart the still-pending row is simply redelivered.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// batchLimit caps how many pending events a single poll delivers, so one
+// slow poll doesn't hold the store open indefinitely.
+const batchLimit = 50
+
+// Dispatcher polls store for pending outbox events and publishes each to
+// bus, marking it dispatched once delivered.
+type Dispatcher struct {
+	store    storage.OutboxStore
+	bus      *events.Bus
+	interval time.Duration
+}
+
+// New builds a Dispatcher that polls store every interval.
+func New(store storage.OutboxStore, bus *events.Bus, interval time.Duration) *Dispatcher {
+	return &Dispatcher{store: store, bus: bus, interval: interval}
+}
+
+// Run polls until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.dispatch(ctx); err != nil {
+			log.Printf("outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) error {
+	pending, err := d.store.PendingOutboxEvents(ctx, batchLimit)
+	if err != nil {
+		return fmt.Errorf("list pending events: %w", err)
+	}
+
+	for _, row := range pending {
+		var event events.Event
+		if err := json.Unmarshal(row.Payload, &event); err != nil {
+			log.Printf("outbox: event #%d: decode payload: %v", row.ID, err)
+			continue
+		}
+		d.bus.Publish(ctx, event)
+		if err := d.store.MarkOutboxDispatched(ctx, row.ID); err != nil {
+			log.Printf("outbox: event #%d: mark dispatched: %v", row.ID, err)
+		}
+	}
+	return nil
+}
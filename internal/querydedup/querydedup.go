@@ -0,0 +1,64 @@
+// Package querydedup coalesces identical concurrent task reads against
+// a storage.Store into a single call, so several requests for the same
+// query arriving at once - e.g. multiple browser tabs polling
+// /api/tasks - don't each run their own query against SQLite.
+package querydedup
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Store wraps a storage.Store, deduplicating concurrent Tasks and Task
+// calls with the same arguments into a single call to the wrapped
+// store; every other storage.Store method passes straight through. The
+// deduplicated call runs with whichever caller's context happened to
+// start it, so a canceled context on one caller doesn't affect the
+// others sharing its result - this is singleflight's usual tradeoff,
+// acceptable here since a task read has no side effects tied to a
+// specific caller.
+type Store struct {
+	storage.Store
+	group singleflight.Group
+}
+
+// New wraps inner with query deduplication.
+func New(inner storage.Store) *Store {
+	return &Store{Store: inner}
+}
+
+func (s *Store) Tasks(ctx context.Context, search string, limit int, starredOnly bool, color storage.TaskColor, recurring *bool, repeatPrefix string) ([]storage.Task, error) {
+	key := fmt.Sprintf("tasks:%d:%s:%t:%s:%s:%s", limit, search, starredOnly, color, recurringKey(recurring), repeatPrefix)
+	v, err, _ := s.group.Do(key, func() (any, error) {
+		return s.Store.Tasks(ctx, search, limit, starredOnly, color, recurring, repeatPrefix)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]storage.Task), nil
+}
+
+// recurringKey renders recurring for use in a dedup key: dereferencing it
+// rather than formatting the pointer itself, whose address is meaningless
+// across calls.
+func recurringKey(recurring *bool) string {
+	if recurring == nil {
+		return ""
+	}
+	return fmt.Sprintf("%t", *recurring)
+}
+
+func (s *Store) Task(ctx context.Context, id int64) (storage.Task, error) {
+	key := fmt.Sprintf("task:%d", id)
+	v, err, _ := s.group.Do(key, func() (any, error) {
+		return s.Store.Task(ctx, id)
+	})
+	if err != nil {
+		return storage.Task{}, err
+	}
+	return v.(storage.Task), nil
+}
@@ -0,0 +1,73 @@
+// Package ratelimit implements a simple per-key token-bucket rate limiter.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a per-key token bucket: each key gets its own bucket that
+// holds up to capacity tokens and refills at rate tokens per second.
+type Limiter struct {
+	rate     float64
+	capacity float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// New returns a Limiter allowing up to capacity requests in a burst per
+// key, refilling at rate requests per second thereafter.
+func New(rate, capacity float64) *Limiter {
+	return &Limiter{rate: rate, capacity: capacity, buckets: make(map[string]*bucket)}
+}
+
+// Allow reports whether key may make a request right now, consuming a
+// token if so. If not, it also returns how long the caller should wait
+// before a token becomes available.
+func (l *Limiter) Allow(key string) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: l.capacity, updated: now}
+		l.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = minFloat(l.capacity, b.tokens+elapsed*l.rate)
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// SetLimits updates the rate and capacity applied to future Allow calls,
+// so a running server's throttling can be retuned - e.g. on a config
+// reload - without losing the per-key buckets already accumulated.
+func (l *Limiter) SetLimits(rate, capacity float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = rate
+	l.capacity = capacity
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,136 @@
+// Package reminder implements a background scheduler that scans the task
+// list for tasks coming due soon and sends a reminder through every
+// enabled notify.Notifier channel once per task occurrence, recording
+// each send so a restart doesn't repeat it.
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/notify"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// tasksLimit caps how many tasks a single scan considers, matching the
+// API's own listing limit (see internal/api.tasksLimit).
+const tasksLimit = 50
+
+// Scheduler scans store for tasks due within their lead time and sends a
+// reminder for each one through notifier, recording the send in store so
+// it survives a restart. Scheduling a recurring scan is the caller's
+// job - see jobs.Scheduler - RunOnce here is a single pass.
+type Scheduler struct {
+	store       storage.Store
+	reminders   storage.ReminderStore
+	notifier    notify.Notifier
+	defaultLead int
+}
+
+// New returns a Scheduler sending reminders for tasks due within
+// defaultLead days (overridable per task via Task.ReminderLeadDays)
+// through notifier. notifier is typically a notify.Multi combining every
+// enabled channel; a nil or empty Multi makes every send a no-op, so
+// RunOnce can always be called unconditionally.
+func New(store storage.Store, reminders storage.ReminderStore, notifier notify.Notifier, defaultLead int) *Scheduler {
+	return &Scheduler{
+		store:       store,
+		reminders:   reminders,
+		notifier:    notifier,
+		defaultLead: defaultLead,
+	}
+}
+
+// RunOnce sends a reminder for every task whose lead time has been
+// reached and that hasn't already received one for its current
+// occurrence.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	tasks, err := s.store.Tasks(ctx, "", tasksLimit, false, "", nil, "")
+	if err != nil {
+		return fmt.Errorf("list tasks: %w", err)
+	}
+
+	today := nextdate.Format(time.Now())
+	for _, t := range tasks {
+		due, err := s.dueForReminder(t, today)
+		if err != nil {
+			log.Printf("reminder: task #%d: %v", t.ID, err)
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		sent, err := s.reminders.ReminderSent(ctx, t.ID, t.Date)
+		if err != nil {
+			log.Printf("reminder: task #%d: check sent: %v", t.ID, err)
+			continue
+		}
+		if sent {
+			continue
+		}
+
+		if err := s.notifier.Send(ctx, formatReminder(t)); err != nil {
+			log.Printf("reminder: task #%d: send: %v", t.ID, err)
+			continue
+		}
+		if err := s.reminders.MarkReminderSent(ctx, t.ID, t.Date); err != nil {
+			log.Printf("reminder: task #%d: mark sent: %v", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// dueForReminder reports whether t's lead time has been reached as of
+// today, i.e. today is on or after t.Date minus its effective lead days.
+// If t has no lead time of its own or the scheduler's default (lead 0)
+// and a due Time, the same-day reminder additionally waits for that time
+// of day, so "dentist at 14:30" doesn't ping first thing in the morning.
+func (s *Scheduler) dueForReminder(t storage.Task, today string) (bool, error) {
+	due, err := nextdate.Parse(t.Date)
+	if err != nil {
+		return false, fmt.Errorf("parse date: %w", err)
+	}
+	lead := t.ReminderLeadDays
+	if lead == 0 {
+		lead = s.defaultLead
+	}
+	remindFrom := due.AddDate(0, 0, -lead)
+	if today < nextdate.Format(remindFrom) {
+		return false, nil
+	}
+	if lead == 0 && t.Time != "" && today == t.Date {
+		dueTime, err := time.Parse("15:04", t.Time)
+		if err != nil {
+			return false, fmt.Errorf("parse time: %w", err)
+		}
+		now := time.Now()
+		return now.Hour() > dueTime.Hour() || (now.Hour() == dueTime.Hour() && now.Minute() >= dueTime.Minute()), nil
+	}
+	return true, nil
+}
+
+// formatReminder renders t as a reminder notify.Event.
+func formatReminder(t storage.Task) notify.Event {
+	due := formatDate(t.Date)
+	if t.Time != "" {
+		due += " " + t.Time
+	}
+	return notify.Event{
+		Title: fmt.Sprintf("Напоминание: %q — срок %s", t.Title, due),
+		Body:  t.Comment,
+	}
+}
+
+// formatDate renders the scheduler's canonical YYYYMMDD date as DD.MM.YYYY
+// for display, matching the web UI's date format.
+func formatDate(date string) string {
+	t, err := nextdate.Parse(date)
+	if err != nil {
+		return date
+	}
+	return t.Format("02.01.2006")
+}
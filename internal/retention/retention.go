@@ -0,0 +1,109 @@
+// Package retention prunes datasets that would otherwise grow
+// unbounded - reminder delivery history, dispatched outbox events, and
+// trashed tasks - under configured age limits, on a schedule (see
+// internal/jobs), and can report what a run would remove without
+// deleting anything, for a dry-run endpoint. Scheduled backups prune
+// themselves on every run instead - see internal/backup.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Config holds how long each dataset is retained. Zero disables that
+// dataset's pruning.
+type Config struct {
+	// ReminderHistoryAge deletes sent_reminders records older than
+	// this.
+	ReminderHistoryAge time.Duration
+	// AuditLogAge deletes dispatched outbox events older than this.
+	AuditLogAge time.Duration
+	// TrashAge permanently removes tasks storage.Store.DeleteTask moved
+	// to trash more than this long ago.
+	TrashAge time.Duration
+}
+
+// Worker prunes storage.RetentionStore under Config on a schedule.
+type Worker struct {
+	cfg   Config
+	store storage.RetentionStore
+}
+
+// New returns a Worker enforcing cfg against store.
+func New(cfg Config, store storage.RetentionStore) *Worker {
+	return &Worker{cfg: cfg, store: store}
+}
+
+// RunOnce deletes everything past the configured retention ages - the
+// job scheduler's entry point.
+func (w *Worker) RunOnce(ctx context.Context) error {
+	_, err := w.run(ctx, true)
+	return err
+}
+
+// DryRun reports what RunOnce would delete right now, without deleting
+// anything.
+func (w *Worker) DryRun(ctx context.Context) (storage.RetentionReport, error) {
+	return w.run(ctx, false)
+}
+
+// run implements both RunOnce and DryRun: apply chooses whether
+// matching rows are actually removed or only counted.
+func (w *Worker) run(ctx context.Context, apply bool) (storage.RetentionReport, error) {
+	var report storage.RetentionReport
+
+	if w.cfg.ReminderHistoryAge > 0 {
+		cutoff := nextdate.Format(time.Now().Add(-w.cfg.ReminderHistoryAge))
+		n, err := w.reminderHistory(ctx, cutoff, apply)
+		if err != nil {
+			return storage.RetentionReport{}, fmt.Errorf("reminder history: %w", err)
+		}
+		report.SentReminders = n
+	}
+
+	if w.cfg.AuditLogAge > 0 {
+		cutoff := time.Now().Add(-w.cfg.AuditLogAge)
+		n, err := w.auditLog(ctx, cutoff, apply)
+		if err != nil {
+			return storage.RetentionReport{}, fmt.Errorf("audit log: %w", err)
+		}
+		report.AuditLogEvents = n
+	}
+
+	if w.cfg.TrashAge > 0 {
+		cutoff := time.Now().Add(-w.cfg.TrashAge)
+		n, err := w.trash(ctx, cutoff, apply)
+		if err != nil {
+			return storage.RetentionReport{}, fmt.Errorf("trash: %w", err)
+		}
+		report.TrashedTasks = n
+	}
+
+	return report, nil
+}
+
+func (w *Worker) reminderHistory(ctx context.Context, cutoff string, apply bool) (int, error) {
+	if apply {
+		return w.store.DeleteSentRemindersBefore(ctx, cutoff)
+	}
+	return w.store.CountSentRemindersBefore(ctx, cutoff)
+}
+
+func (w *Worker) auditLog(ctx context.Context, cutoff time.Time, apply bool) (int, error) {
+	if apply {
+		return w.store.DeleteDispatchedOutboxBefore(ctx, cutoff)
+	}
+	return w.store.CountDispatchedOutboxBefore(ctx, cutoff)
+}
+
+func (w *Worker) trash(ctx context.Context, cutoff time.Time, apply bool) (int, error) {
+	if apply {
+		return w.store.DeleteTrashedTasksBefore(ctx, cutoff)
+	}
+	return w.store.CountTrashedTasksBefore(ctx, cutoff)
+}
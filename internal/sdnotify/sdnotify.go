@@ -0,0 +1,35 @@
+// Package sdnotify sends service state notifications to systemd, for
+// deployments that run the server under a Type=notify unit. Notify is a
+// no-op whenever NOTIFY_SOCKET isn't set, so it's safe to call
+// unconditionally on any platform or supervisor.
+package sdnotify
+
+import (
+	"net"
+	"os"
+)
+
+// Ready notifies systemd that startup has finished, so a unit with
+// Type=notify can unblock units ordered After= it.
+func Ready() error {
+	return notify("READY=1")
+}
+
+// Stopping notifies systemd that a graceful shutdown has begun.
+func Stopping() error {
+	return notify("STOPPING=1")
+}
+
+func notify(state string) error {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return nil
+	}
+	conn, err := net.Dial("unixgram", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
@@ -0,0 +1,162 @@
+// Package slack integrates the scheduler with a Slack workspace: request
+// signature verification and slash-command parsing for the inbound /todo
+// command, and a Notifier for posting daily summaries to an incoming
+// webhook.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/notify"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// maxTimestampSkew bounds how old a slash command request's timestamp may
+// be, per Slack's own recommendation, so a captured request can't be
+// replayed indefinitely.
+const maxTimestampSkew = 5 * time.Minute
+
+// VerifySignature reports whether signature is a valid Slack request
+// signature for body, per Slack's signing scheme: HMAC-SHA256 of
+// "v0:{timestamp}:{body}" keyed by signingSecret, hex-encoded and
+// prefixed "v0=". now is the time to check timestamp's age against,
+// passed in rather than read internally so callers can test this
+// deterministically.
+func VerifySignature(signingSecret, timestamp string, body []byte, signature string, now time.Time) bool {
+	if signingSecret == "" || timestamp == "" || signature == "" {
+		return false
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(ts, 0))
+	if age < 0 {
+		age = -age
+	}
+	if age > maxTimestampSkew {
+		return false
+	}
+
+	base := "v0:" + timestamp + ":" + string(body)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(base))
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Command is the subset of Slack's slash-command payload the scheduler
+// acts on. See https://api.slack.com/interactivity/slash-commands for the
+// full field list.
+type Command struct {
+	Command     string
+	Text        string
+	UserName    string
+	ChannelID   string
+	ResponseURL string
+}
+
+// ParseCommand extracts a Command from a slash command request's decoded
+// form body.
+func ParseCommand(form url.Values) Command {
+	return Command{
+		Command:     form.Get("command"),
+		Text:        form.Get("text"),
+		UserName:    form.Get("user_name"),
+		ChannelID:   form.Get("channel_id"),
+		ResponseURL: form.Get("response_url"),
+	}
+}
+
+// Notifier posts daily task summaries to a Slack incoming webhook. A nil
+// *Notifier is valid and PostSummary becomes a no-op, matching how the
+// rest of the server treats optional integrations (see
+// internal/errorreporter.Reporter).
+type Notifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewNotifier returns a Notifier that posts to webhookURL, or nil if
+// webhookURL is empty, disabling daily summaries.
+func NewNotifier(webhookURL string) *Notifier {
+	if webhookURL == "" {
+		return nil
+	}
+	return &Notifier{webhookURL: webhookURL, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+type webhookMessage struct {
+	Text string `json:"text"`
+}
+
+// PostSummary posts a formatted summary of tasks to the configured
+// webhook. A nil Notifier or an empty tasks slice still posts (an empty
+// list is itself useful information: nothing is due), matching the
+// simplicity of the rest of this integration.
+func (n *Notifier) PostSummary(tasks []storage.Task) error {
+	return n.PostMessage(FormatSummary(tasks))
+}
+
+// PostMessage posts text to the configured webhook as a plain Slack
+// message. A nil Notifier is a no-op.
+func (n *Notifier) PostMessage(text string) error {
+	if n == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(webhookMessage{Text: text})
+	if err != nil {
+		return fmt.Errorf("encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post slack message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("post slack message: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Send implements notify.Notifier, posting event as a Slack message with
+// its Title and Body joined on a newline. A nil Notifier is a no-op.
+func (n *Notifier) Send(_ context.Context, event notify.Event) error {
+	text := event.Title
+	if event.Body != "" {
+		text += "\n" + event.Body
+	}
+	return n.PostMessage(text)
+}
+
+// FormatSummary renders tasks as a Slack message: one bulleted line per
+// task, "date — title".
+func FormatSummary(tasks []storage.Task) string {
+	if len(tasks) == 0 {
+		return "На сегодня задач нет."
+	}
+	msg := fmt.Sprintf("Задачи на сегодня (%d):\n", len(tasks))
+	for _, t := range tasks {
+		msg += fmt.Sprintf("• %s — %s\n", t.Date, t.Title)
+	}
+	return msg
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAPITokenNotFound is returned when an API token lookup finds no
+// matching record.
+var ErrAPITokenNotFound = errors.New("api token not found")
+
+// APIToken is a long-lived, named personal access token used by
+// automation clients instead of a password-based session.
+type APIToken struct {
+	ID         int64
+	Name       string
+	Hash       string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// APITokenStore persists personal access tokens, hashed at rest, along
+// with per-token last-used tracking.
+type APITokenStore interface {
+	CreateAPIToken(ctx context.Context, name, hash string, createdAt time.Time) (int64, error)
+	ListAPITokens(ctx context.Context) ([]APIToken, error)
+	APITokenByHash(ctx context.Context, hash string) (APIToken, error)
+	DeleteAPIToken(ctx context.Context, id int64) error
+	TouchAPIToken(ctx context.Context, id int64, lastUsedAt time.Time) error
+}
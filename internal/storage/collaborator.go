@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// Permission is the access level granted to a signed-in identity on the
+// shared task list.
+type Permission string
+
+const (
+	// PermissionRead allows viewing tasks but not creating, editing,
+	// deleting or completing them.
+	PermissionRead Permission = "read"
+	// PermissionWrite allows full access to the shared task list. The
+	// owner (password-based sign-in) always has write permission.
+	PermissionWrite Permission = "write"
+)
+
+// ErrCollaboratorNotFound is returned when a collaborator lookup finds no
+// matching record.
+var ErrCollaboratorNotFound = errors.New("collaborator not found")
+
+// Collaborator is another person the owner has shared the task list with,
+// signed in separately from the owner's password.
+type Collaborator struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	Permission   Permission
+}
+
+// CollaboratorStore persists the people the owner has shared the task
+// list with, and the permission level each was granted.
+type CollaboratorStore interface {
+	CreateCollaborator(ctx context.Context, username, passwordHash string, permission Permission) (int64, error)
+	ListCollaborators(ctx context.Context) ([]Collaborator, error)
+	CollaboratorByUsername(ctx context.Context, username string) (Collaborator, error)
+	UpdateCollaboratorPassword(ctx context.Context, id int64, passwordHash string) error
+	DeleteCollaborator(ctx context.Context, id int64) error
+}
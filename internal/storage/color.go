@@ -0,0 +1,34 @@
+package storage
+
+import "regexp"
+
+// TaskColor is a color label attached to a task, either one of the
+// named palette entries or a "#RRGGBB" hex value, for a UI that wants
+// to distinguish categories of tasks at a glance without full tag
+// support.
+type TaskColor string
+
+// Named palette colors a client can rely on having a consistent
+// meaning across installs, as opposed to an arbitrary hex value.
+const (
+	ColorRed    TaskColor = "red"
+	ColorOrange TaskColor = "orange"
+	ColorYellow TaskColor = "yellow"
+	ColorGreen  TaskColor = "green"
+	ColorBlue   TaskColor = "blue"
+	ColorPurple TaskColor = "purple"
+	ColorGray   TaskColor = "gray"
+)
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Valid reports whether c is a recognized palette name, a "#RRGGBB" hex
+// value, or empty - a task with no color label.
+func (c TaskColor) Valid() bool {
+	switch c {
+	case "", ColorRed, ColorOrange, ColorYellow, ColorGreen, ColorBlue, ColorPurple, ColorGray:
+		return true
+	default:
+		return hexColor.MatchString(string(c))
+	}
+}
@@ -0,0 +1,24 @@
+package storage
+
+import "context"
+
+// GoogleTaskMapping links a local task to the Google Tasks entry it's
+// mirrored to, plus the Google side's last-seen "updated" timestamp
+// (RFC 3339), so the sync worker can tell whether Google's copy changed
+// since it last looked.
+type GoogleTaskMapping struct {
+	TaskID        int64  `db:"task_id" json:"task_id"`
+	GoogleTaskID  string `db:"google_task_id" json:"google_task_id"`
+	GoogleUpdated string `db:"google_updated" json:"google_updated"`
+}
+
+// GoogleSyncStore persists the mapping between local tasks and their
+// mirrored Google Tasks entries.
+type GoogleSyncStore interface {
+	// GoogleMappings returns every local task's mapping.
+	GoogleMappings(ctx context.Context) ([]GoogleTaskMapping, error)
+	// UpsertGoogleMapping creates or updates the mapping for m.TaskID.
+	UpsertGoogleMapping(ctx context.Context, m GoogleTaskMapping) error
+	// DeleteGoogleMapping removes the mapping for taskID, if any.
+	DeleteGoogleMapping(ctx context.Context, taskID int64) error
+}
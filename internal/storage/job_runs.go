@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// JobRun is the outcome of one completed run of a background job, as
+// recorded by JobRuns.
+type JobRun struct {
+	Name    string    `json:"name" db:"name"`
+	RanAt   time.Time `json:"ran_at" db:"ran_at"`
+	OK      bool      `json:"ok" db:"ok"`
+	Message string    `json:"message" db:"message"`
+}
+
+// JobRuns persists the last outcome of each named background job, so a
+// jobs.Scheduler restarted by a process restart can tell whether a job
+// with an interval longer than the process has been up is actually due,
+// instead of running it immediately every time.
+type JobRuns interface {
+	// LastJobRun returns the most recently recorded run for name, or the
+	// zero JobRun and false if name has never been recorded.
+	LastJobRun(ctx context.Context, name string) (JobRun, bool, error)
+	// RecordJobRun upserts the outcome of the most recent run of name.
+	RecordJobRun(ctx context.Context, run JobRun) error
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LinkRelation describes how one task relates to another.
+type LinkRelation string
+
+const (
+	// LinkRelated marks two tasks as related without either superseding
+	// the other.
+	LinkRelated LinkRelation = "related"
+	// LinkDuplicateOf marks a task as a duplicate of the linked task.
+	LinkDuplicateOf LinkRelation = "duplicate_of"
+)
+
+// Valid reports whether r is one of the recognized relations.
+func (r LinkRelation) Valid() bool {
+	switch r {
+	case LinkRelated, LinkDuplicateOf:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrLinkNotFound is returned when a link lookup or delete targets an id
+// that doesn't exist.
+var ErrLinkNotFound = errors.New("link not found")
+
+// TaskLink is a directed relationship from TaskID to RelatedTaskID - e.g.
+// TaskID is a "duplicate_of" RelatedTaskID.
+type TaskLink struct {
+	ID            int64        `db:"id" json:"id" xml:"id"`
+	TaskID        int64        `db:"task_id" json:"task_id" xml:"task_id"`
+	RelatedTaskID int64        `db:"related_task_id" json:"related_task_id" xml:"related_task_id"`
+	Relation      LinkRelation `db:"relation" json:"relation" xml:"relation"`
+	CreatedAt     time.Time    `db:"created_at" json:"created_at" xml:"created_at"`
+}
+
+// LinkStore persists relationships between tasks, a capability alongside
+// the main Store the same way NoteStore and TimeTracker are.
+type LinkStore interface {
+	// AddLink records taskID's relation to relatedTaskID and returns it
+	// with its assigned ID.
+	AddLink(ctx context.Context, taskID, relatedTaskID int64, relation LinkRelation, createdAt time.Time) (TaskLink, error)
+	// LinksForTask returns every link with taskID on either side, oldest
+	// first.
+	LinksForTask(ctx context.Context, taskID int64) ([]TaskLink, error)
+	// RemoveLink removes id, returning ErrLinkNotFound if it doesn't
+	// exist.
+	RemoveLink(ctx context.Context, id int64) error
+	// AllLinks returns every recorded link, for a full-dataset export.
+	AllLinks(ctx context.Context) ([]TaskLink, error)
+	// RestoreLinks re-records every link in links, preserving each one's
+	// ID.
+	RestoreLinks(ctx context.Context, links []TaskLink) error
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// MaintenanceReport summarizes the result of a database maintenance
+// operation, so the CLI and the admin API can report it the same way.
+type MaintenanceReport struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+// ReplicationStatus reports the write-ahead-log state a continuous
+// replication tool (e.g. Litestream) needs to decide when it's safe to
+// ship WAL frames and fold them into the main database file.
+type ReplicationStatus struct {
+	JournalMode string `json:"journal_mode"`
+	// WALAutocheckpoint is the configured wal_autocheckpoint threshold,
+	// in pages. Zero means SQLite's own automatic checkpointing is
+	// disabled, leaving checkpoints entirely to an external tool.
+	WALAutocheckpoint int `json:"wal_autocheckpoint"`
+	// WALSizeBytes is the current size of the -wal file, or zero if the
+	// database isn't in WAL mode or has no pending WAL frames.
+	WALSizeBytes int64 `json:"wal_size_bytes"`
+}
+
+// RepositoryMethodStats summarizes the timing of one Store method's calls
+// since the process started, so an operator can see which repository
+// calls are slow without guessing from request latency alone.
+type RepositoryMethodStats struct {
+	Method string `json:"method"`
+	// Count is how many times Method has been called.
+	Count int64 `json:"count"`
+	// TotalTime is the sum of every call's duration.
+	TotalTime time.Duration `json:"total_time_ns"`
+	// AvgTime is TotalTime divided by Count; zero if Count is zero.
+	AvgTime time.Duration `json:"avg_time_ns"`
+	// MaxTime is the slowest call observed.
+	MaxTime time.Duration `json:"max_time_ns"`
+	// SlowCount is how many calls exceeded the configured slow-query
+	// threshold.
+	SlowCount int64 `json:"slow_count"`
+}
+
+// Maintainer runs administrative operations against the database engine
+// itself, so operators can inspect and repair storage without stopping
+// the server or reaching for a raw sqlite3 shell.
+type Maintainer interface {
+	// IntegrityCheck runs the engine's consistency check and reports
+	// what it found.
+	IntegrityCheck(ctx context.Context) (MaintenanceReport, error)
+	// Vacuum rebuilds the database file to reclaim space freed by
+	// deletes and defragment it.
+	Vacuum(ctx context.Context) (MaintenanceReport, error)
+	// Backup writes a consistent snapshot of the database to path,
+	// safe to run against a database still serving requests.
+	Backup(ctx context.Context, path string) (MaintenanceReport, error)
+	// Checkpoint folds WAL frames back into the main database file.
+	// mode is one of the sqlite3 wal_checkpoint modes - PASSIVE, FULL,
+	// RESTART or TRUNCATE - and defaults to PASSIVE when empty, the
+	// only mode guaranteed not to block concurrent readers or writers.
+	Checkpoint(ctx context.Context, mode string) (MaintenanceReport, error)
+	// ReplicationStatus reports the current WAL configuration and size,
+	// for a status endpoint a replication tool can poll.
+	ReplicationStatus(ctx context.Context) (ReplicationStatus, error)
+	// RepositoryMetrics reports per-method call counts and latency for
+	// the task repository's hot methods.
+	RepositoryMetrics(ctx context.Context) ([]RepositoryMethodStats, error)
+	// QuickCheck runs SQLite's quick_check pragma, a cheaper but
+	// non-exhaustive alternative to IntegrityCheck suited to running on
+	// a schedule rather than on demand.
+	QuickCheck(ctx context.Context) (MaintenanceReport, error)
+	// IncrementalVacuum reclaims up to pages freelist pages without the
+	// full rebuild VACUUM performs, so it can run periodically against
+	// a live database without the exclusive lock VACUUM needs. It only
+	// has an effect on a database opened with auto_vacuum=INCREMENTAL;
+	// pages <= 0 reclaims as many pages as are available.
+	IncrementalVacuum(ctx context.Context, pages int) (MaintenanceReport, error)
+}
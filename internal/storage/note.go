@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoteNotFound is returned when a note lookup or delete targets an id
+// that doesn't exist.
+var ErrNoteNotFound = errors.New("note not found")
+
+// TaskNote is one timestamped entry in a task's activity feed - a note
+// added alongside the single Comment field, when a task needs a running
+// log rather than one comment that gets overwritten.
+type TaskNote struct {
+	ID        int64     `db:"id" json:"id"`
+	TaskID    int64     `db:"task_id" json:"task_id"`
+	Body      string    `db:"body" json:"body"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// NoteStore persists a task's activity feed of notes, a capability
+// alongside the main Store the same way ReminderStore and TimeTracker
+// are.
+type NoteStore interface {
+	// AddNote appends a note to taskID's activity feed and returns it
+	// with its assigned ID.
+	AddNote(ctx context.Context, taskID int64, body string, createdAt time.Time) (TaskNote, error)
+	// Notes returns taskID's activity feed, oldest first.
+	Notes(ctx context.Context, taskID int64) ([]TaskNote, error)
+	// DeleteNote removes id, returning ErrNoteNotFound if it doesn't
+	// exist.
+	DeleteNote(ctx context.Context, id int64) error
+	// AllNotes returns every recorded note, for a full-dataset export.
+	AllNotes(ctx context.Context) ([]TaskNote, error)
+	// RestoreNotes re-records every note in notes, preserving each one's
+	// ID.
+	RestoreNotes(ctx context.Context, notes []TaskNote) error
+}
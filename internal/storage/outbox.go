@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxEvent is a domain event persisted alongside the task mutation that
+// produced it, awaiting delivery by the outbox dispatcher.
+type OutboxEvent struct {
+	ID        int64     `db:"id"`
+	Type      string    `db:"event_type"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// OutboxStore gives the outbox dispatcher access to events queued by task
+// mutations, so delivery can be retried across restarts instead of relying
+// on an in-memory publish that a crash would lose.
+type OutboxStore interface {
+	// PendingOutboxEvents returns up to limit not-yet-dispatched events,
+	// oldest first.
+	PendingOutboxEvents(ctx context.Context, limit int) ([]OutboxEvent, error)
+	// MarkOutboxDispatched records that event id was delivered, so it isn't
+	// redelivered on the next poll.
+	MarkOutboxDispatched(ctx context.Context, id int64) error
+}
+
+// EventHistory gives read access to every event the outbox has ever
+// recorded, dispatched or not, for reporting that needs the full history
+// rather than just what's pending delivery - see the /api/stats endpoint.
+type EventHistory interface {
+	// EventsSince returns every event recorded at or after since,
+	// oldest first.
+	EventsSince(ctx context.Context, since time.Time) ([]OutboxEvent, error)
+}
@@ -0,0 +1,28 @@
+package storage
+
+import "context"
+
+// SentReminder records that a reminder was sent for a task's occurrence
+// due on a given date.
+type SentReminder struct {
+	TaskID int64  `db:"task_id" json:"task_id"`
+	Date   string `db:"date" json:"date"`
+}
+
+// ReminderStore tracks which task reminders have already been sent, so a
+// restart of the reminder scheduler doesn't resend one.
+type ReminderStore interface {
+	// ReminderSent reports whether a reminder was already sent for task
+	// id's occurrence due on date.
+	ReminderSent(ctx context.Context, id int64, date string) (bool, error)
+	// MarkReminderSent records that a reminder was sent for task id's
+	// occurrence due on date.
+	MarkReminderSent(ctx context.Context, id int64, date string) error
+	// AllSentReminders returns every recorded reminder delivery, for a
+	// full-dataset export.
+	AllSentReminders(ctx context.Context) ([]SentReminder, error)
+	// RestoreSentReminders re-records every reminder delivery in records,
+	// skipping any that are already present so restoring into a database
+	// that already has some history doesn't fail or duplicate rows.
+	RestoreSentReminders(ctx context.Context, records []SentReminder) error
+}
@@ -0,0 +1,172 @@
+// Package repositorytest is a shared conformance suite for
+// storage.Store implementations. A backend package proves it satisfies
+// the contract storage.Store promises by adding a _test.go file that
+// constructs the backend and calls Run against it - see
+// storage/sqlite's repository_test.go for the sqlite backend's use of
+// it. Any future backend (Postgres, an in-memory store for tests, etc.)
+// should do the same rather than re-deriving these cases.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// Run exercises store against the behavior storage.Store documents:
+// ordering, not-found semantics, unicode search and context
+// cancellation. newStore is called once per subtest so cases don't leak
+// state into each other; it is responsible for returning a store backed
+// by a fresh, empty dataset.
+func Run(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	t.Run("OrderedByDate", func(t *testing.T) { testOrderedByDate(t, newStore) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newStore) })
+	t.Run("UnicodeSearch", func(t *testing.T) { testUnicodeSearch(t, newStore) })
+	t.Run("ContextCancellation", func(t *testing.T) { testContextCancellation(t, newStore) })
+	t.Run("RecurringFilter", func(t *testing.T) { testRecurringFilter(t, newStore) })
+	t.Run("StarredFilter", func(t *testing.T) { testStarredFilter(t, newStore) })
+	t.Run("ColorFilter", func(t *testing.T) { testColorFilter(t, newStore) })
+}
+
+func testOrderedByDate(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	dates := []string{"20260301", "20260101", "20260215"}
+	for _, d := range dates {
+		_, err := store.AddTask(ctx, storage.Task{Date: d, Title: "task " + d})
+		require.NoError(t, err)
+	}
+
+	tasks, err := store.Tasks(ctx, "", 10, false, "", nil, "")
+	require.NoError(t, err)
+	require.Len(t, tasks, len(dates))
+	assert.Equal(t, []string{"20260101", "20260215", "20260301"}, []string{
+		tasks[0].Date, tasks[1].Date, tasks[2].Date,
+	})
+}
+
+func testNotFound(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.Task(ctx, 999999)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = store.UpdateTask(ctx, storage.Task{ID: 999999, Date: "20260101", Title: "missing"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = store.DeleteTask(ctx, 999999)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testUnicodeSearch(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "Оплатить счёт"})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260102", Title: "Buy groceries"})
+	require.NoError(t, err)
+
+	tasks, err := store.Tasks(ctx, "счёт", 10, false, "", nil, "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Оплатить счёт", tasks[0].Title)
+}
+
+func testRecurringFilter(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "one-off"})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "daily", Repeat: "d 1"})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "weekly", Repeat: "w 1"})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "yearly", Repeat: "y"})
+	require.NoError(t, err)
+
+	recurring := true
+	tasks, err := store.Tasks(ctx, "", 10, false, "", &recurring, "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+
+	notRecurring := false
+	tasks, err = store.Tasks(ctx, "", 10, false, "", &notRecurring, "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "one-off", tasks[0].Title)
+
+	tasks, err = store.Tasks(ctx, "", 10, false, "", nil, "w")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "weekly", tasks[0].Title)
+
+	// "y" has no trailing " N" like "d"/"w"/"m" do, so it needs its own
+	// exact-match branch in the LIKE query rather than falling out of it.
+	tasks, err = store.Tasks(ctx, "", 10, false, "", nil, "y")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "yearly", tasks[0].Title)
+}
+
+func testStarredFilter(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "plain"})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "important", Starred: true})
+	require.NoError(t, err)
+
+	tasks, err := store.Tasks(ctx, "", 10, true, "", nil, "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "important", tasks[0].Title)
+
+	tasks, err = store.Tasks(ctx, "", 10, false, "", nil, "")
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+}
+
+func testColorFilter(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "uncolored"})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "red one", Color: storage.ColorRed})
+	require.NoError(t, err)
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "blue one", Color: storage.ColorBlue})
+	require.NoError(t, err)
+
+	tasks, err := store.Tasks(ctx, "", 10, false, storage.ColorRed, nil, "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "red one", tasks[0].Title)
+
+	// An empty color means no color filtering at all, not "match uncolored
+	// tasks only".
+	tasks, err = store.Tasks(ctx, "", 10, false, "", nil, "")
+	require.NoError(t, err)
+	assert.Len(t, tasks, 3)
+}
+
+func testContextCancellation(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	store := newStore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := store.Tasks(ctx, "", 10, false, "", nil, "")
+	assert.Error(t, err)
+
+	_, err = store.AddTask(ctx, storage.Task{Date: "20260101", Title: "should not be added"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionReport counts what a RetentionStore prune call removed (or,
+// in dry-run mode, would remove).
+type RetentionReport struct {
+	SentReminders  int `json:"sent_reminders"`
+	AuditLogEvents int `json:"audit_log_events"`
+	TrashedTasks   int `json:"trashed_tasks"`
+}
+
+// RetentionStore counts and deletes the datasets internal/retention
+// prunes on a schedule: reminder delivery history, dispatched outbox
+// events (the audit trail of task mutations), and trashed tasks. It
+// backs both the retention job and the dry-run report endpoint, so both
+// share exactly the same cutoff logic.
+type RetentionStore interface {
+	// CountSentRemindersBefore reports how many sent_reminders rows are
+	// dated before cutoff (a "YYYYMMDD" date, the same format as
+	// storage.Task.Date).
+	CountSentRemindersBefore(ctx context.Context, cutoff string) (int, error)
+	// DeleteSentRemindersBefore deletes those rows and returns how many
+	// were removed.
+	DeleteSentRemindersBefore(ctx context.Context, cutoff string) (int, error)
+	// CountDispatchedOutboxBefore reports how many already-dispatched
+	// outbox events were created before cutoff. Pending events are
+	// never counted, dispatched or not.
+	CountDispatchedOutboxBefore(ctx context.Context, cutoff time.Time) (int, error)
+	// DeleteDispatchedOutboxBefore deletes those events and returns how
+	// many were removed.
+	DeleteDispatchedOutboxBefore(ctx context.Context, cutoff time.Time) (int, error)
+	// CountTrashedTasksBefore reports how many tasks Store.DeleteTask
+	// moved to trash before cutoff.
+	CountTrashedTasksBefore(ctx context.Context, cutoff time.Time) (int, error)
+	// DeleteTrashedTasksBefore permanently removes those trashed tasks
+	// and returns how many were removed.
+	DeleteTrashedTasksBefore(ctx context.Context, cutoff time.Time) (int, error)
+}
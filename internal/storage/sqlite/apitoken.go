@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func (s *Store) CreateAPIToken(ctx context.Context, name, hash string, createdAt time.Time) (int64, error) {
+	res, err := s.execWrite(ctx,
+		`INSERT INTO api_tokens (name, hash, created_at) VALUES (?, ?, ?)`, name, hash, createdAt)
+	if err != nil {
+		return 0, fmt.Errorf("create api token: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+func (s *Store) ListAPITokens(ctx context.Context) ([]storage.APIToken, error) {
+	rows, err := s.db.QueryxContext(ctx,
+		`SELECT id, name, hash, created_at, last_used_at FROM api_tokens ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []storage.APIToken{}
+	for rows.Next() {
+		var t storage.APIToken
+		if err := rows.Scan(&t.ID, &t.Name, &t.Hash, &t.CreatedAt, &t.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("scan api token: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (s *Store) APITokenByHash(ctx context.Context, hash string) (storage.APIToken, error) {
+	var t storage.APIToken
+	err := s.db.QueryRowxContext(ctx,
+		`SELECT id, name, hash, created_at, last_used_at FROM api_tokens WHERE hash = ?`, hash).
+		Scan(&t.ID, &t.Name, &t.Hash, &t.CreatedAt, &t.LastUsedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.APIToken{}, storage.ErrAPITokenNotFound
+	}
+	if err != nil {
+		return storage.APIToken{}, fmt.Errorf("get api token: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Store) DeleteAPIToken(ctx context.Context, id int64) error {
+	res, err := s.execWrite(ctx, `DELETE FROM api_tokens WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete api token: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (s *Store) TouchAPIToken(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	if _, err := s.execWrite(ctx,
+		`UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, lastUsedAt, id); err != nil {
+		return fmt.Errorf("touch api token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestAPITokenLifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.APITokenByHash(ctx, "unknown-hash")
+	assert.ErrorIs(t, err, storage.ErrAPITokenNotFound)
+
+	createdAt := time.Now().Truncate(time.Second)
+	id, err := store.CreateAPIToken(ctx, "ci-runner", "hash-1", createdAt)
+	require.NoError(t, err)
+	assert.NotZero(t, id)
+
+	tokens, err := store.ListAPITokens(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "ci-runner", tokens[0].Name)
+	assert.Nil(t, tokens[0].LastUsedAt)
+
+	got, err := store.APITokenByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+
+	usedAt := createdAt.Add(time.Minute)
+	require.NoError(t, store.TouchAPIToken(ctx, id, usedAt))
+	got, err = store.APITokenByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.WithinDuration(t, usedAt, *got.LastUsedAt, time.Second)
+
+	require.NoError(t, store.DeleteAPIToken(ctx, id))
+	_, err = store.APITokenByHash(ctx, "hash-1")
+	assert.ErrorIs(t, err, storage.ErrAPITokenNotFound)
+
+	err = store.DeleteAPIToken(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func (s *Store) CreateCollaborator(ctx context.Context, username, passwordHash string, permission storage.Permission) (int64, error) {
+	res, err := s.execWrite(ctx,
+		`INSERT INTO collaborators (username, password_hash, permission) VALUES (?, ?, ?)`,
+		username, passwordHash, permission)
+	if err != nil {
+		return 0, fmt.Errorf("create collaborator: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+func (s *Store) ListCollaborators(ctx context.Context) ([]storage.Collaborator, error) {
+	rows, err := s.db.QueryxContext(ctx,
+		`SELECT id, username, password_hash, permission FROM collaborators ORDER BY username`)
+	if err != nil {
+		return nil, fmt.Errorf("list collaborators: %w", err)
+	}
+	defer rows.Close()
+
+	collaborators := []storage.Collaborator{}
+	for rows.Next() {
+		var c storage.Collaborator
+		if err := rows.Scan(&c.ID, &c.Username, &c.PasswordHash, &c.Permission); err != nil {
+			return nil, fmt.Errorf("scan collaborator: %w", err)
+		}
+		collaborators = append(collaborators, c)
+	}
+	return collaborators, rows.Err()
+}
+
+func (s *Store) CollaboratorByUsername(ctx context.Context, username string) (storage.Collaborator, error) {
+	var c storage.Collaborator
+	err := s.db.QueryRowxContext(ctx,
+		`SELECT id, username, password_hash, permission FROM collaborators WHERE username = ?`, username).
+		Scan(&c.ID, &c.Username, &c.PasswordHash, &c.Permission)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.Collaborator{}, storage.ErrCollaboratorNotFound
+	}
+	if err != nil {
+		return storage.Collaborator{}, fmt.Errorf("get collaborator: %w", err)
+	}
+	return c, nil
+}
+
+func (s *Store) UpdateCollaboratorPassword(ctx context.Context, id int64, passwordHash string) error {
+	res, err := s.execWrite(ctx, `UPDATE collaborators SET password_hash = ? WHERE id = ?`, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("update collaborator password: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (s *Store) DeleteCollaborator(ctx context.Context, id int64) error {
+	res, err := s.execWrite(ctx, `DELETE FROM collaborators WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete collaborator: %w", err)
+	}
+	return checkAffected(res)
+}
@@ -0,0 +1,28 @@
+//go:build !nocgo
+
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverName is the database/sql driver this package opens, and the
+// build tag controlling which one: the default build links mattn's cgo
+// binding to the real SQLite C library; building with -tags nocgo
+// switches to the pure Go modernc.org/sqlite binding in driver_nocgo.go,
+// at the cost of that build's slower query performance, for targets
+// (e.g. cross-compiling to ARM without a C toolchain) where cgo isn't
+// an option.
+const driverName = "sqlite3"
+
+// isBusyOrLockedErr reports whether err is a SQLITE_BUSY or
+// SQLITE_LOCKED error, as raised by the mattn/go-sqlite3 driver.
+func isBusyOrLockedErr(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
@@ -0,0 +1,39 @@
+//go:build nocgo
+
+package sqlite
+
+import (
+	"errors"
+
+	modernc "modernc.org/sqlite"
+)
+
+// driverName is the database/sql driver this package opens. See
+// driver_cgo.go for the default, cgo-based build.
+const driverName = "sqlite"
+
+// sqliteBusyCode and sqliteLockedCode are SQLite's own SQLITE_BUSY (5)
+// and SQLITE_LOCKED (6) primary result codes - stable across every
+// SQLite binding, including this pure Go one - masked out of whatever
+// extended result code modernc.org/sqlite reports.
+// See https://www.sqlite.org/rescode.html.
+const (
+	sqliteBusyCode   = 5
+	sqliteLockedCode = 6
+	primaryCodeMask  = 0xff
+)
+
+// isBusyOrLockedErr reports whether err is a SQLITE_BUSY or
+// SQLITE_LOCKED error, as raised by the modernc.org/sqlite driver.
+func isBusyOrLockedErr(err error) bool {
+	var sqliteErr *modernc.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() & primaryCodeMask {
+	case sqliteBusyCode, sqliteLockedCode:
+		return true
+	default:
+		return false
+	}
+}
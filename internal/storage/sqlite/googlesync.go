@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// GoogleMappings returns every local task's Google Tasks mapping.
+func (s *Store) GoogleMappings(ctx context.Context) ([]storage.GoogleTaskMapping, error) {
+	mappings := []storage.GoogleTaskMapping{}
+	err := s.db.SelectContext(ctx, &mappings,
+		`SELECT task_id, google_task_id, google_updated FROM google_task_mappings ORDER BY task_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list google task mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// UpsertGoogleMapping creates or updates the mapping for m.TaskID.
+func (s *Store) UpsertGoogleMapping(ctx context.Context, m storage.GoogleTaskMapping) error {
+	_, err := s.execWrite(ctx,
+		`INSERT INTO google_task_mappings (task_id, google_task_id, google_updated) VALUES (?, ?, ?)
+		 ON CONFLICT (task_id) DO UPDATE SET google_task_id = excluded.google_task_id, google_updated = excluded.google_updated`,
+		m.TaskID, m.GoogleTaskID, m.GoogleUpdated)
+	if err != nil {
+		return fmt.Errorf("upsert google task mapping: %w", err)
+	}
+	return nil
+}
+
+// DeleteGoogleMapping removes the mapping for taskID, if any.
+func (s *Store) DeleteGoogleMapping(ctx context.Context, taskID int64) error {
+	_, err := s.execWrite(ctx, `DELETE FROM google_task_mappings WHERE task_id = ?`, taskID)
+	if err != nil {
+		return fmt.Errorf("delete google task mapping: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestGoogleMappingLifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "synced task"})
+	require.NoError(t, err)
+
+	mappings, err := store.GoogleMappings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, mappings)
+
+	require.NoError(t, store.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{
+		TaskID: id, GoogleTaskID: "g-1", GoogleUpdated: "2026-01-01T00:00:00Z",
+	}))
+
+	mappings, err = store.GoogleMappings(ctx)
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+	assert.Equal(t, "g-1", mappings[0].GoogleTaskID)
+
+	// Upserting the same task_id again updates in place rather than adding
+	// a second row.
+	require.NoError(t, store.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{
+		TaskID: id, GoogleTaskID: "g-1", GoogleUpdated: "2026-02-01T00:00:00Z",
+	}))
+	mappings, err = store.GoogleMappings(ctx)
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+	assert.Equal(t, "2026-02-01T00:00:00Z", mappings[0].GoogleUpdated)
+
+	require.NoError(t, store.DeleteGoogleMapping(ctx, id))
+	mappings, err = store.GoogleMappings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, mappings)
+
+	// Deleting a mapping that doesn't exist is a no-op, not an error.
+	require.NoError(t, store.DeleteGoogleMapping(ctx, id))
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// LastJobRun returns the most recently recorded run of name.
+func (s *Store) LastJobRun(ctx context.Context, name string) (storage.JobRun, bool, error) {
+	var run storage.JobRun
+	err := s.db.QueryRowxContext(ctx,
+		`SELECT name, ran_at, ok, message FROM job_runs WHERE name = ?`, name).StructScan(&run)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.JobRun{}, false, nil
+	}
+	if err != nil {
+		return storage.JobRun{}, false, fmt.Errorf("last job run %q: %w", name, err)
+	}
+	return run, true, nil
+}
+
+// RecordJobRun upserts the outcome of the most recent run of run.Name.
+func (s *Store) RecordJobRun(ctx context.Context, run storage.JobRun) error {
+	_, err := s.execWrite(ctx,
+		`INSERT INTO job_runs (name, ran_at, ok, message) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (name) DO UPDATE SET ran_at = excluded.ran_at, ok = excluded.ok, message = excluded.message`,
+		run.Name, run.RanAt.UTC(), run.OK, run.Message)
+	if err != nil {
+		return fmt.Errorf("record job run %q: %w", run.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestJobRunLifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, ok, err := store.LastJobRun(ctx, "retention")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ranAt := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, store.RecordJobRun(ctx, storage.JobRun{Name: "retention", RanAt: ranAt, OK: true, Message: "removed 3 rows"}))
+
+	run, ok, err := store.LastJobRun(ctx, "retention")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, run.OK)
+	assert.Equal(t, "removed 3 rows", run.Message)
+
+	// Recording a later run for the same name upserts in place rather
+	// than keeping history.
+	nextRun := ranAt.Add(24 * time.Hour)
+	require.NoError(t, store.RecordJobRun(ctx, storage.JobRun{Name: "retention", RanAt: nextRun, OK: false, Message: "database busy"}))
+
+	run, ok, err = store.LastJobRun(ctx, "retention")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.False(t, run.OK)
+	assert.Equal(t, "database busy", run.Message)
+
+	// A different job name is tracked independently.
+	_, ok, err = store.LastJobRun(ctx, "backup")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
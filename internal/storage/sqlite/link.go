@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// AddLink records taskID's relation to relatedTaskID and returns it with
+// its assigned ID.
+func (s *Store) AddLink(ctx context.Context, taskID, relatedTaskID int64, relation storage.LinkRelation, createdAt time.Time) (storage.TaskLink, error) {
+	var link storage.TaskLink
+	err := s.withTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO task_links (task_id, related_task_id, relation, created_at) VALUES (?, ?, ?, ?)`,
+			taskID, relatedTaskID, relation, createdAt.UTC())
+		if err != nil {
+			return fmt.Errorf("insert link: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("last insert id: %w", err)
+		}
+		link = storage.TaskLink{ID: id, TaskID: taskID, RelatedTaskID: relatedTaskID, Relation: relation, CreatedAt: createdAt.UTC()}
+		return nil
+	})
+	return link, err
+}
+
+// LinksForTask returns every link with taskID on either side, oldest
+// first.
+func (s *Store) LinksForTask(ctx context.Context, taskID int64) ([]storage.TaskLink, error) {
+	links := []storage.TaskLink{}
+	err := s.db.SelectContext(ctx, &links,
+		`SELECT id, task_id, related_task_id, relation, created_at FROM task_links
+		 WHERE task_id = ? OR related_task_id = ? ORDER BY created_at, id`, taskID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("list links for task %d: %w", taskID, err)
+	}
+	return links, nil
+}
+
+// RemoveLink removes id, returning storage.ErrLinkNotFound if it doesn't
+// exist.
+func (s *Store) RemoveLink(ctx context.Context, id int64) error {
+	res, err := s.execWrite(ctx, `DELETE FROM task_links WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete link: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return storage.ErrLinkNotFound
+	}
+	return nil
+}
+
+// AllLinks returns every recorded link, for a full-dataset export.
+func (s *Store) AllLinks(ctx context.Context) ([]storage.TaskLink, error) {
+	links := []storage.TaskLink{}
+	err := s.db.SelectContext(ctx, &links,
+		`SELECT id, task_id, related_task_id, relation, created_at FROM task_links ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list links: %w", err)
+	}
+	return links, nil
+}
+
+// RestoreLinks re-records every link in links, preserving each one's ID.
+func (s *Store) RestoreLinks(ctx context.Context, links []storage.TaskLink) error {
+	return s.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, l := range links {
+			_, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO task_links (id, task_id, related_task_id, relation, created_at) VALUES (?, ?, ?, ?, ?)`,
+				l.ID, l.TaskID, l.RelatedTaskID, l.Relation, l.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("restore link %d: %w", l.ID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestLinkLifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	a, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task a"})
+	require.NoError(t, err)
+	b, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task b"})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	link, err := store.AddLink(ctx, a, b, storage.LinkDuplicateOf, createdAt)
+	require.NoError(t, err)
+	assert.NotZero(t, link.ID)
+
+	// LinksForTask must find the link from either side of the relation.
+	forA, err := store.LinksForTask(ctx, a)
+	require.NoError(t, err)
+	require.Len(t, forA, 1)
+	forB, err := store.LinksForTask(ctx, b)
+	require.NoError(t, err)
+	require.Len(t, forB, 1)
+	assert.Equal(t, link.ID, forB[0].ID)
+
+	require.NoError(t, store.RemoveLink(ctx, link.ID))
+	forA, err = store.LinksForTask(ctx, a)
+	require.NoError(t, err)
+	assert.Empty(t, forA)
+
+	err = store.RemoveLink(ctx, link.ID)
+	assert.ErrorIs(t, err, storage.ErrLinkNotFound)
+}
+
+func TestAllLinksAndRestore(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	a, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task a"})
+	require.NoError(t, err)
+	b, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task b"})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err = store.AddLink(ctx, a, b, storage.LinkRelated, createdAt)
+	require.NoError(t, err)
+
+	all, err := store.AllLinks(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	restored := []storage.TaskLink{
+		{ID: 77, TaskID: b, RelatedTaskID: a, Relation: storage.LinkDuplicateOf, CreatedAt: createdAt.Add(time.Hour)},
+	}
+	require.NoError(t, store.RestoreLinks(ctx, restored))
+
+	all, err = store.AllLinks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	// Restoring the same link again must not duplicate or error.
+	require.NoError(t, store.RestoreLinks(ctx, restored))
+	all, err = store.AllLinks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestLinkRelationValid(t *testing.T) {
+	assert.True(t, storage.LinkRelated.Valid())
+	assert.True(t, storage.LinkDuplicateOf.Valid())
+	assert.False(t, storage.LinkRelation("bogus").Valid())
+}
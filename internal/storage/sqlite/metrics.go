@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// instrumentedMethods are the Store methods timed by instrument, and the
+// full set RepositoryMetrics reports on.
+var instrumentedMethods = []string{
+	"AddTask", "Task", "UpdateTask", "DeleteTask", "CompleteTask", "SkipTask",
+	"Tasks", "CountTasks", "TasksDueBy", "TasksInRange", "TasksStartableBy", "StreamTasks", "ImportTasks", "RestoreTasks", "RescheduleTasks",
+}
+
+// methodMetrics accumulates timing for one Store method across every call
+// made against it.
+type methodMetrics struct {
+	mu        sync.Mutex
+	count     int64
+	total     time.Duration
+	max       time.Duration
+	slowCount int64
+}
+
+func (m *methodMetrics) record(d time.Duration, slowThreshold time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.count++
+	m.total += d
+	if d > m.max {
+		m.max = d
+	}
+	if slowThreshold > 0 && d > slowThreshold {
+		m.slowCount++
+	}
+}
+
+func (m *methodMetrics) snapshot() (count int64, total, max time.Duration, slowCount int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count, m.total, m.max, m.slowCount
+}
+
+func newMethodMetrics() map[string]*methodMetrics {
+	m := make(map[string]*methodMetrics, len(instrumentedMethods))
+	for _, name := range instrumentedMethods {
+		m[name] = &methodMetrics{}
+	}
+	return m
+}
+
+// redacted marks a bound parameter that instrument callers deliberately
+// leave out of slow-query logs, e.g. free-text task titles, comments and
+// search terms, since logging arbitrary user content to a shared
+// operational log is worth avoiding even though it's not a credential.
+const redacted = "[REDACTED]"
+
+// instrument runs fn, recording its duration in s.metrics[name] and, if it
+// exceeds s.slowQueryThreshold, logging it alongside args. Callers pass
+// only the bound parameters safe to log, substituting redacted for
+// anything free-text.
+func (s *Store) instrument(name string, args []any, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	d := time.Since(start)
+
+	s.metrics[name].record(d, s.slowQueryThreshold)
+	if s.slowQueryThreshold > 0 && d > s.slowQueryThreshold {
+		log.Printf("sqlite: slow query: %s%v took %s", name, args, d)
+	}
+	return err
+}
+
+// RepositoryMetrics reports call count and latency for every instrumented
+// Store method, sorted by name.
+func (s *Store) RepositoryMetrics(ctx context.Context) ([]storage.RepositoryMethodStats, error) {
+	stats := make([]storage.RepositoryMethodStats, 0, len(instrumentedMethods))
+	for _, name := range instrumentedMethods {
+		count, total, max, slowCount := s.metrics[name].snapshot()
+		var avg time.Duration
+		if count > 0 {
+			avg = total / time.Duration(count)
+		}
+		stats = append(stats, storage.RepositoryMethodStats{
+			Method:    name,
+			Count:     count,
+			TotalTime: total,
+			AvgTime:   avg,
+			MaxTime:   max,
+			SlowCount: slowCount,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Method < stats[j].Method })
+	return stats, nil
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// AddNote appends a note to taskID's activity feed and returns it with its
+// assigned ID.
+func (s *Store) AddNote(ctx context.Context, taskID int64, body string, createdAt time.Time) (storage.TaskNote, error) {
+	var note storage.TaskNote
+	err := s.withTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO task_notes (task_id, body, created_at) VALUES (?, ?, ?)`,
+			taskID, body, createdAt.UTC())
+		if err != nil {
+			return fmt.Errorf("insert note: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("last insert id: %w", err)
+		}
+		note = storage.TaskNote{ID: id, TaskID: taskID, Body: body, CreatedAt: createdAt.UTC()}
+		return nil
+	})
+	return note, err
+}
+
+// Notes returns taskID's activity feed, oldest first.
+func (s *Store) Notes(ctx context.Context, taskID int64) ([]storage.TaskNote, error) {
+	notes := []storage.TaskNote{}
+	err := s.db.SelectContext(ctx, &notes,
+		`SELECT id, task_id, body, created_at FROM task_notes WHERE task_id = ? ORDER BY created_at, id`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("list notes for task %d: %w", taskID, err)
+	}
+	return notes, nil
+}
+
+// DeleteNote removes id, returning storage.ErrNoteNotFound if it doesn't
+// exist.
+func (s *Store) DeleteNote(ctx context.Context, id int64) error {
+	res, err := s.execWrite(ctx, `DELETE FROM task_notes WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete note: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return storage.ErrNoteNotFound
+	}
+	return nil
+}
+
+// AllNotes returns every recorded note, for a full-dataset export.
+func (s *Store) AllNotes(ctx context.Context) ([]storage.TaskNote, error) {
+	notes := []storage.TaskNote{}
+	err := s.db.SelectContext(ctx, &notes,
+		`SELECT id, task_id, body, created_at FROM task_notes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	return notes, nil
+}
+
+// RestoreNotes re-records every note in notes, preserving each one's ID.
+func (s *Store) RestoreNotes(ctx context.Context, notes []storage.TaskNote) error {
+	return s.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, n := range notes {
+			_, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO task_notes (id, task_id, body, created_at) VALUES (?, ?, ?, ?)`,
+				n.ID, n.TaskID, n.Body, n.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("restore note %d: %w", n.ID, err)
+			}
+		}
+		return nil
+	})
+}
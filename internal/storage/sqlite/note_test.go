@@ -0,0 +1,77 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestNoteLifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task"})
+	require.NoError(t, err)
+
+	notes, err := store.Notes(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	note, err := store.AddNote(ctx, id, "first note", createdAt)
+	require.NoError(t, err)
+	assert.NotZero(t, note.ID)
+
+	_, err = store.AddNote(ctx, id, "second note", createdAt.Add(time.Minute))
+	require.NoError(t, err)
+
+	notes, err = store.Notes(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, "first note", notes[0].Body)
+	assert.Equal(t, "second note", notes[1].Body)
+
+	require.NoError(t, store.DeleteNote(ctx, note.ID))
+	notes, err = store.Notes(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "second note", notes[0].Body)
+
+	err = store.DeleteNote(ctx, note.ID)
+	assert.ErrorIs(t, err, storage.ErrNoteNotFound)
+}
+
+func TestAllNotesAndRestore(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task"})
+	require.NoError(t, err)
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, err = store.AddNote(ctx, id, "note", createdAt)
+	require.NoError(t, err)
+
+	all, err := store.AllNotes(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	restored := []storage.TaskNote{
+		{ID: 99, TaskID: id, Body: "restored note", CreatedAt: createdAt.Add(time.Hour)},
+	}
+	require.NoError(t, store.RestoreNotes(ctx, restored))
+
+	all, err = store.AllNotes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	// Restoring the same note again must not duplicate or error.
+	require.NoError(t, store.RestoreNotes(ctx, restored))
+	all, err = store.AllNotes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// enqueueOutbox records event within tx, so it commits or rolls back with
+// the task mutation that produced it.
+func enqueueOutbox(ctx context.Context, tx *sqlx.Tx, eventType events.Type, t storage.Task) error {
+	payload, err := json.Marshal(events.Event{Type: eventType, Task: t})
+	if err != nil {
+		return fmt.Errorf("encode outbox event: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO outbox (event_type, payload, created_at) VALUES (?, ?, ?)`,
+		string(eventType), payload, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("enqueue outbox event: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) PendingOutboxEvents(ctx context.Context, limit int) ([]storage.OutboxEvent, error) {
+	rows, err := s.db.QueryxContext(ctx,
+		`SELECT id, event_type, payload, created_at FROM outbox WHERE dispatched_at IS NULL ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query pending outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []storage.OutboxEvent{}
+	for rows.Next() {
+		var e storage.OutboxEvent
+		if err := rows.StructScan(&e); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (s *Store) MarkOutboxDispatched(ctx context.Context, id int64) error {
+	res, err := s.execWrite(ctx, `UPDATE outbox SET dispatched_at = ? WHERE id = ?`, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("mark outbox event dispatched: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (s *Store) EventsSince(ctx context.Context, since time.Time) ([]storage.OutboxEvent, error) {
+	rows, err := s.db.QueryxContext(ctx,
+		`SELECT id, event_type, payload, created_at FROM outbox WHERE created_at >= ? ORDER BY created_at`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query outbox events since %s: %w", since, err)
+	}
+	defer rows.Close()
+
+	events := []storage.OutboxEvent{}
+	for rows.Next() {
+		var e storage.OutboxEvent
+		if err := rows.StructScan(&e); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// CountDispatchedOutboxBefore reports how many already-dispatched
+// outbox events were created before cutoff.
+func (s *Store) CountDispatchedOutboxBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	var count int
+	if err := s.db.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM outbox WHERE dispatched_at IS NOT NULL AND created_at < ?`, cutoff.UTC()); err != nil {
+		return 0, fmt.Errorf("count dispatched outbox events before %s: %w", cutoff, err)
+	}
+	return count, nil
+}
+
+// DeleteDispatchedOutboxBefore deletes already-dispatched outbox events
+// created before cutoff and returns how many were removed. Pending
+// events are never deleted, regardless of age.
+func (s *Store) DeleteDispatchedOutboxBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	res, err := s.execWrite(ctx,
+		`DELETE FROM outbox WHERE dispatched_at IS NOT NULL AND created_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete dispatched outbox events before %s: %w", cutoff, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,69 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// The outbox has no public write path of its own - events are enqueued as
+// a side effect of task mutations within the same transaction (see
+// enqueueOutbox in outbox.go), so that's how these tests populate it.
+
+func TestOutboxPendingAndDispatch(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task one"})
+	require.NoError(t, err)
+
+	pending, err := store.PendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, string(events.TaskCreated), pending[0].Type)
+	assert.Contains(t, string(pending[0].Payload), "task one")
+
+	require.NoError(t, store.MarkOutboxDispatched(ctx, pending[0].ID))
+
+	pending, err = store.PendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	require.NoError(t, store.DeleteTask(ctx, id))
+	pending, err = store.PendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, string(events.TaskDeleted), pending[0].Type)
+}
+
+func TestOutboxMarkDispatchedNotFound(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	err := store.MarkOutboxDispatched(ctx, 999999)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestEventsSince(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task one"})
+	require.NoError(t, err)
+
+	// EventsSince returns full history, dispatched or not - it backs
+	// reporting, not delivery.
+	all, err := store.EventsSince(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	future, err := store.EventsSince(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, future)
+}
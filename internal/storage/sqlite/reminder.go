@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// ReminderSent reports whether a reminder was already sent for task id's
+// occurrence due on date.
+func (s *Store) ReminderSent(ctx context.Context, id int64, date string) (bool, error) {
+	var exists int
+	err := s.db.GetContext(ctx, &exists,
+		`SELECT 1 FROM sent_reminders WHERE task_id = ? AND date = ?`, id, date)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check reminder sent: %w", err)
+	}
+	return true, nil
+}
+
+// MarkReminderSent records that a reminder was sent for task id's
+// occurrence due on date.
+func (s *Store) MarkReminderSent(ctx context.Context, id int64, date string) error {
+	_, err := s.execWrite(ctx,
+		`INSERT OR IGNORE INTO sent_reminders (task_id, date) VALUES (?, ?)`, id, date)
+	if err != nil {
+		return fmt.Errorf("mark reminder sent: %w", err)
+	}
+	return nil
+}
+
+// AllSentReminders returns every recorded reminder delivery, for a
+// full-dataset export.
+func (s *Store) AllSentReminders(ctx context.Context) ([]storage.SentReminder, error) {
+	records := []storage.SentReminder{}
+	err := s.db.SelectContext(ctx, &records,
+		`SELECT task_id, date FROM sent_reminders ORDER BY task_id, date`)
+	if err != nil {
+		return nil, fmt.Errorf("list sent reminders: %w", err)
+	}
+	return records, nil
+}
+
+// RestoreSentReminders re-records every reminder delivery in records,
+// skipping any that are already present.
+func (s *Store) RestoreSentReminders(ctx context.Context, records []storage.SentReminder) error {
+	return s.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, rec := range records {
+			_, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO sent_reminders (task_id, date) VALUES (?, ?)`, rec.TaskID, rec.Date)
+			if err != nil {
+				return fmt.Errorf("restore sent reminder: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// CountSentRemindersBefore reports how many sent_reminders rows are
+// dated before cutoff.
+func (s *Store) CountSentRemindersBefore(ctx context.Context, cutoff string) (int, error) {
+	var count int
+	if err := s.db.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM sent_reminders WHERE date < ?`, cutoff); err != nil {
+		return 0, fmt.Errorf("count sent reminders before %s: %w", cutoff, err)
+	}
+	return count, nil
+}
+
+// DeleteSentRemindersBefore deletes sent_reminders rows dated before
+// cutoff and returns how many were removed.
+func (s *Store) DeleteSentRemindersBefore(ctx context.Context, cutoff string) (int, error) {
+	res, err := s.execWrite(ctx, `DELETE FROM sent_reminders WHERE date < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete sent reminders before %s: %w", cutoff, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return int(n), nil
+}
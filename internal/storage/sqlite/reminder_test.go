@@ -0,0 +1,60 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestReminderSentTracking(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "pay rent"})
+	require.NoError(t, err)
+
+	sent, err := store.ReminderSent(ctx, id, "20260101")
+	require.NoError(t, err)
+	assert.False(t, sent)
+
+	require.NoError(t, store.MarkReminderSent(ctx, id, "20260101"))
+	sent, err = store.ReminderSent(ctx, id, "20260101")
+	require.NoError(t, err)
+	assert.True(t, sent)
+
+	// A different occurrence of the same task is tracked independently.
+	sent, err = store.ReminderSent(ctx, id, "20260201")
+	require.NoError(t, err)
+	assert.False(t, sent)
+
+	// Marking the same occurrence twice must not error (INSERT OR IGNORE).
+	require.NoError(t, store.MarkReminderSent(ctx, id, "20260101"))
+
+	all, err := store.AllSentReminders(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, storage.SentReminder{TaskID: id, Date: "20260101"}, all[0])
+}
+
+func TestRestoreSentReminders(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "pay rent"})
+	require.NoError(t, err)
+	require.NoError(t, store.MarkReminderSent(ctx, id, "20260101"))
+
+	records := []storage.SentReminder{
+		{TaskID: id, Date: "20260101"}, // already present, must not duplicate or error
+		{TaskID: id, Date: "20260201"},
+	}
+	require.NoError(t, store.RestoreSentReminders(ctx, records))
+
+	all, err := store.AllSentReminders(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
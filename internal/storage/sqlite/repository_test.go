@@ -0,0 +1,32 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/repositorytest"
+	"github.com/ngavrilenkov/go_final_project/internal/storage/sqlite"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) storage.Store {
+		return newStore(t)
+	})
+}
+
+// newStore opens a fresh, empty *sqlite.Store backed by a temp file,
+// closing it when t ends. It's the shared fixture for every test in
+// this package, not just the storage.Store conformance suite above -
+// the capability interfaces (SessionStore, ReminderStore, TimeTracker,
+// and the rest) are all satisfied by the same concrete Store.
+func newStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "scheduler.db")
+	store, err := sqlite.Open(file, sqlite.Options{})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
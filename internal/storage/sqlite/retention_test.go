@@ -0,0 +1,111 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestSentReminderRetention(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	old, err := store.AddTask(ctx, storage.Task{Date: "20250101", Title: "old task"})
+	require.NoError(t, err)
+	recent, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "recent task"})
+	require.NoError(t, err)
+	require.NoError(t, store.MarkReminderSent(ctx, old, "20250101"))
+	require.NoError(t, store.MarkReminderSent(ctx, recent, "20260101"))
+
+	count, err := store.CountSentRemindersBefore(ctx, "20260101")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	n, err := store.DeleteSentRemindersBefore(ctx, "20260101")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	all, err := store.AllSentReminders(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, recent, all[0].TaskID)
+}
+
+func TestDispatchedOutboxRetention(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task"})
+	require.NoError(t, err)
+
+	pending, err := store.PendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	future := time.Now().Add(time.Hour)
+
+	// Pending (undispatched) events are never counted or deleted,
+	// regardless of age.
+	count, err := store.CountDispatchedOutboxBefore(ctx, future)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	require.NoError(t, store.MarkOutboxDispatched(ctx, pending[0].ID))
+
+	count, err = store.CountDispatchedOutboxBefore(ctx, future)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	past := time.Now().Add(-time.Hour)
+	count, err = store.CountDispatchedOutboxBefore(ctx, past)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	n, err := store.DeleteDispatchedOutboxBefore(ctx, future)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	all, err := store.EventsSince(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestTrashRetention(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "old task"})
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteTask(ctx, id))
+
+	_, err = store.Task(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	count, err := store.CountTrashedTasksBefore(ctx, past)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = store.CountTrashedTasksBefore(ctx, future)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	n, err := store.DeleteTrashedTasksBefore(ctx, past)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = store.DeleteTrashedTasksBefore(ctx, future)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	count, err = store.CountTrashedTasksBefore(ctx, future)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (s *Store) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := s.execWrite(ctx,
+		`INSERT OR REPLACE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`, jti, expiresAt)
+	if err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var expiresAt time.Time
+	err := s.db.QueryRowxContext(ctx,
+		`SELECT expires_at FROM revoked_tokens WHERE jti = ?`, jti).Scan(&expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check revoked token: %w", err)
+	}
+	return time.Now().Before(expiresAt), nil
+}
@@ -0,0 +1,40 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeToken(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	revoked, err := store.IsTokenRevoked(ctx, "unknown-jti")
+	require.NoError(t, err)
+	assert.False(t, revoked, "a jti that was never revoked must not be reported as revoked")
+
+	require.NoError(t, store.RevokeToken(ctx, "jti-1", time.Now().Add(time.Hour)))
+	revoked, err = store.IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// A revocation entry whose expiry has already passed protects nothing
+	// anymore - the access token it was guarding against would already be
+	// rejected on expiry alone, so IsTokenRevoked should say no rather than
+	// keep flagging it forever.
+	require.NoError(t, store.RevokeToken(ctx, "jti-2", time.Now().Add(-time.Hour)))
+	revoked, err = store.IsTokenRevoked(ctx, "jti-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// INSERT OR REPLACE means revoking the same jti twice just updates the
+	// expiry rather than erroring.
+	require.NoError(t, store.RevokeToken(ctx, "jti-1", time.Now().Add(2*time.Hour)))
+	revoked, err = store.IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,929 @@
+// Package sqlite is the SQLite-backed implementation of storage.Store.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+const schema = `
+CREATE TABLE scheduler (
+	id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+	date               CHAR(8)      NOT NULL DEFAULT '',
+	title              VARCHAR(256) NOT NULL DEFAULT '',
+	comment            TEXT         NOT NULL DEFAULT '',
+	repeat             VARCHAR(128) NOT NULL DEFAULT '',
+	reminder_lead_days INTEGER      NOT NULL DEFAULT 0,
+	is_habit           INTEGER      NOT NULL DEFAULT 0,
+	repeat_anchor      CHAR(8)      NOT NULL DEFAULT '',
+	time               CHAR(5)      NOT NULL DEFAULT '',
+	duration_minutes   INTEGER      NOT NULL DEFAULT 0,
+	start_date         CHAR(8)      NOT NULL DEFAULT '',
+	pinned             INTEGER      NOT NULL DEFAULT 0,
+	starred            INTEGER      NOT NULL DEFAULT 0,
+	color              VARCHAR(16)  NOT NULL DEFAULT ''
+);
+CREATE INDEX scheduler_date ON scheduler (date);
+CREATE INDEX scheduler_date_id ON scheduler (date, id);
+CREATE INDEX scheduler_title_nocase ON scheduler (title COLLATE NOCASE);
+CREATE INDEX scheduler_comment_nocase ON scheduler (comment COLLATE NOCASE);
+
+CREATE TABLE sent_reminders (
+	task_id INTEGER NOT NULL,
+	date    CHAR(8) NOT NULL,
+	PRIMARY KEY (task_id, date)
+);
+
+CREATE TABLE outbox (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_type    VARCHAR(64) NOT NULL,
+	payload       TEXT        NOT NULL,
+	created_at    DATETIME    NOT NULL,
+	dispatched_at DATETIME
+);
+CREATE INDEX outbox_pending ON outbox (id) WHERE dispatched_at IS NULL;
+
+CREATE TABLE refresh_tokens (
+	hash       TEXT PRIMARY KEY,
+	expires_at DATETIME NOT NULL,
+	subject    TEXT NOT NULL DEFAULT '',
+	permission TEXT NOT NULL DEFAULT 'write'
+);
+
+CREATE TABLE revoked_tokens (
+	jti        TEXT PRIMARY KEY,
+	expires_at DATETIME NOT NULL
+);
+
+CREATE TABLE api_tokens (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	name         VARCHAR(256) NOT NULL,
+	hash         TEXT NOT NULL UNIQUE,
+	created_at   DATETIME NOT NULL,
+	last_used_at DATETIME
+);
+
+CREATE TABLE collaborators (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	username      VARCHAR(256) NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	permission    TEXT NOT NULL
+);
+
+CREATE TABLE google_task_mappings (
+	task_id        INTEGER PRIMARY KEY,
+	google_task_id TEXT NOT NULL UNIQUE,
+	google_updated TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE job_runs (
+	name    TEXT PRIMARY KEY,
+	ran_at  DATETIME NOT NULL,
+	ok      INTEGER  NOT NULL,
+	message TEXT     NOT NULL DEFAULT ''
+);
+
+CREATE TABLE time_entries (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id    INTEGER  NOT NULL,
+	started_at DATETIME NOT NULL,
+	stopped_at DATETIME
+);
+CREATE INDEX time_entries_task_id ON time_entries (task_id);
+
+CREATE TABLE task_notes (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id    INTEGER  NOT NULL,
+	body       TEXT     NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE INDEX task_notes_task_id ON task_notes (task_id);
+
+CREATE TABLE task_links (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id         INTEGER      NOT NULL,
+	related_task_id INTEGER      NOT NULL,
+	relation        VARCHAR(32)  NOT NULL,
+	created_at      DATETIME     NOT NULL
+);
+CREATE INDEX task_links_task_id ON task_links (task_id);
+CREATE INDEX task_links_related_task_id ON task_links (related_task_id);
+
+CREATE TABLE trash (
+	task_id            INTEGER PRIMARY KEY,
+	date               CHAR(8)      NOT NULL DEFAULT '',
+	title              VARCHAR(256) NOT NULL DEFAULT '',
+	comment            TEXT         NOT NULL DEFAULT '',
+	repeat             VARCHAR(128) NOT NULL DEFAULT '',
+	reminder_lead_days INTEGER      NOT NULL DEFAULT 0,
+	is_habit           INTEGER      NOT NULL DEFAULT 0,
+	repeat_anchor      CHAR(8)      NOT NULL DEFAULT '',
+	time               CHAR(5)      NOT NULL DEFAULT '',
+	duration_minutes   INTEGER      NOT NULL DEFAULT 0,
+	start_date         CHAR(8)      NOT NULL DEFAULT '',
+	pinned             INTEGER      NOT NULL DEFAULT 0,
+	starred            INTEGER      NOT NULL DEFAULT 0,
+	color              VARCHAR(16)  NOT NULL DEFAULT '',
+	trashed_at         DATETIME     NOT NULL
+);
+CREATE INDEX trash_trashed_at ON trash (trashed_at);
+`
+
+// migrateIndexes adds indexes introduced after the initial schema to a
+// database that already exists, since schema above only runs on first
+// create. There's no versioned migration system here - each index is
+// just applied idempotently on every Open.
+const migrateIndexes = `
+CREATE INDEX IF NOT EXISTS scheduler_date_id ON scheduler (date, id);
+CREATE INDEX IF NOT EXISTS scheduler_title_nocase ON scheduler (title COLLATE NOCASE);
+CREATE INDEX IF NOT EXISTS scheduler_comment_nocase ON scheduler (comment COLLATE NOCASE);
+`
+
+// migrateColumns adds columns introduced after the initial schema to a
+// database that already exists, the same way migrateIndexes does for
+// indexes.
+const migrateColumns = `
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS is_habit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS repeat_anchor CHAR(8) NOT NULL DEFAULT '';
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS time CHAR(5) NOT NULL DEFAULT '';
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS duration_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS start_date CHAR(8) NOT NULL DEFAULT '';
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS pinned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS starred INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scheduler ADD COLUMN IF NOT EXISTS color VARCHAR(16) NOT NULL DEFAULT '';
+`
+
+// migrateTables adds tables introduced after the initial schema to a
+// database that already exists, the same way migrateIndexes does for
+// indexes.
+const migrateTables = `
+CREATE TABLE IF NOT EXISTS job_runs (
+	name    TEXT PRIMARY KEY,
+	ran_at  DATETIME NOT NULL,
+	ok      INTEGER  NOT NULL,
+	message TEXT     NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS time_entries (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id    INTEGER  NOT NULL,
+	started_at DATETIME NOT NULL,
+	stopped_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS time_entries_task_id ON time_entries (task_id);
+
+CREATE TABLE IF NOT EXISTS task_notes (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id    INTEGER  NOT NULL,
+	body       TEXT     NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS task_notes_task_id ON task_notes (task_id);
+
+CREATE TABLE IF NOT EXISTS task_links (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	task_id         INTEGER      NOT NULL,
+	related_task_id INTEGER      NOT NULL,
+	relation        VARCHAR(32)  NOT NULL,
+	created_at      DATETIME     NOT NULL
+);
+CREATE INDEX IF NOT EXISTS task_links_task_id ON task_links (task_id);
+CREATE INDEX IF NOT EXISTS task_links_related_task_id ON task_links (related_task_id);
+
+CREATE TABLE IF NOT EXISTS trash (
+	task_id            INTEGER PRIMARY KEY,
+	date               CHAR(8)      NOT NULL DEFAULT '',
+	title              VARCHAR(256) NOT NULL DEFAULT '',
+	comment            TEXT         NOT NULL DEFAULT '',
+	repeat             VARCHAR(128) NOT NULL DEFAULT '',
+	reminder_lead_days INTEGER      NOT NULL DEFAULT 0,
+	is_habit           INTEGER      NOT NULL DEFAULT 0,
+	repeat_anchor      CHAR(8)      NOT NULL DEFAULT '',
+	time               CHAR(5)      NOT NULL DEFAULT '',
+	duration_minutes   INTEGER      NOT NULL DEFAULT 0,
+	start_date         CHAR(8)      NOT NULL DEFAULT '',
+	pinned             INTEGER      NOT NULL DEFAULT 0,
+	starred            INTEGER      NOT NULL DEFAULT 0,
+	color              VARCHAR(16)  NOT NULL DEFAULT '',
+	trashed_at         DATETIME     NOT NULL
+);
+CREATE INDEX IF NOT EXISTS trash_trashed_at ON trash (trashed_at);
+`
+
+// dateQueryLayout is the format the search endpoint accepts for exact-date
+// filters, matching what the web UI sends.
+const dateQueryLayout = "02.01.2006"
+
+// Store is a storage.Store backed by a SQLite file.
+type Store struct {
+	db   *sqlx.DB
+	file string
+
+	// writeMu serializes write transactions in this process. SQLite
+	// only ever allows one writer at a time regardless of how many
+	// connections are open; without this, concurrent writers just pile
+	// up contending for that single lock and time out against
+	// Options.BusyTimeout unpredictably instead of queuing cleanly.
+	writeMu sync.Mutex
+
+	// Prepared statements for the hottest queries, parsed once in Open
+	// and reused for the life of the Store instead of being re-parsed
+	// and re-planned by SQLite on every call.
+	addTaskStmt    *sqlx.Stmt
+	taskStmt       *sqlx.Stmt
+	updateTaskStmt *sqlx.Stmt
+
+	// metrics accumulates per-method call counts and latency, reported
+	// through RepositoryMetrics.
+	metrics map[string]*methodMetrics
+	// slowQueryThreshold is copied from Options.SlowQueryThreshold; a
+	// call exceeding it is logged. Zero disables slow-query logging.
+	slowQueryThreshold time.Duration
+
+	// writeRetryDeadline is copied from Options.WriteRetryDeadline.
+	writeRetryDeadline time.Duration
+}
+
+// Options holds the connection-level SQLite settings and connection
+// pool tuning Open applies, so callers can tune locking, consistency
+// and concurrency behavior for their workload instead of living with
+// SQLite's and database/sql's defaults.
+type Options struct {
+	// WALAutocheckpoint is the wal_autocheckpoint threshold, in pages. 0
+	// disables SQLite's own automatic checkpointing, leaving checkpoints
+	// entirely to an external tool such as Litestream.
+	WALAutocheckpoint int
+	// BusyTimeout is how long a connection waits on a locked database
+	// before returning "database is locked", instead of failing
+	// immediately.
+	BusyTimeout time.Duration
+	// ForeignKeys enables foreign key constraint enforcement, off by
+	// default in SQLite for backwards compatibility.
+	ForeignKeys bool
+
+	// MaxOpenConns caps the number of open connections. SQLite handles
+	// concurrent readers fine in WAL mode, so this mainly bounds how
+	// many reads can run at once; writes are serialized separately by
+	// Store regardless of this setting.
+	MaxOpenConns int
+	// MaxIdleConns caps how many idle connections are kept open for
+	// reuse rather than closed.
+	MaxIdleConns int
+	// ConnMaxLifetime closes a connection after it's been open this
+	// long, even if idle. Zero means connections are never force-closed
+	// for age.
+	ConnMaxLifetime time.Duration
+
+	// SlowQueryThreshold logs a warning for any repository call taking
+	// longer than this, with free-text bound parameters redacted. Zero
+	// disables slow-query logging; call counts and latency are still
+	// collected either way and available through RepositoryMetrics.
+	SlowQueryThreshold time.Duration
+
+	// WriteRetryDeadline bounds how long a write retries after a
+	// SQLITE_BUSY or SQLITE_LOCKED error before giving up and returning
+	// it, on top of the per-statement wait BusyTimeout already grants.
+	// This covers contention BusyTimeout alone can leave exposed, e.g. a
+	// backup or CLI import holding the write lock across several of
+	// SQLite's own busy-timeout windows. Zero disables retrying.
+	WriteRetryDeadline time.Duration
+}
+
+// Open connects to the SQLite database at file, creating it and the
+// scheduler schema on first run. It puts the database in WAL mode,
+// which continuous replication tools like Litestream require, and
+// applies opts for the rest of the connection's locking, consistency
+// and pooling behavior.
+func Open(file string, opts Options) (*Store, error) {
+	_, statErr := os.Stat(file)
+	needsInit := errors.Is(statErr, os.ErrNotExist)
+
+	db, err := sqlx.Connect(driverName, file)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite db: %w", err)
+	}
+
+	if needsInit {
+		// auto_vacuum can only be changed on an empty database (or via a
+		// full VACUUM afterward), and must be set before journal_mode
+		// switches to WAL below - SQLite silently ignores the change
+		// once the database is in WAL mode. A database that already
+		// existed before this setting was introduced won't benefit from
+		// IncrementalVacuum without a manual VACUUM to opt it in.
+		if _, err := db.Exec(`PRAGMA auto_vacuum = INCREMENTAL`); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("set auto_vacuum: %w", err)
+		}
+	}
+
+	if _, err := db.Exec(`PRAGMA journal_mode = WAL`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enable WAL mode: %w", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf(`PRAGMA wal_autocheckpoint = %d`, opts.WALAutocheckpoint)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("set wal_autocheckpoint: %w", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf(`PRAGMA busy_timeout = %d`, opts.BusyTimeout.Milliseconds())); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("set busy_timeout: %w", err)
+	}
+	foreignKeys := "OFF"
+	if opts.ForeignKeys {
+		foreignKeys = "ON"
+	}
+	if _, err := db.Exec(`PRAGMA foreign_keys = ` + foreignKeys); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("set foreign_keys: %w", err)
+	}
+
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+
+	if needsInit {
+		if _, err := db.Exec(schema); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("init schema: %w", err)
+		}
+	} else {
+		if _, err := db.Exec(migrateIndexes); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("migrate indexes: %w", err)
+		}
+		if _, err := db.Exec(migrateColumns); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("migrate columns: %w", err)
+		}
+		if _, err := db.Exec(migrateTables); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("migrate tables: %w", err)
+		}
+	}
+
+	s := &Store{db: db, file: file, metrics: newMethodMetrics(), slowQueryThreshold: opts.SlowQueryThreshold, writeRetryDeadline: opts.WriteRetryDeadline}
+	if err := s.prepareStatements(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// prepareStatements parses the hottest queries once so AddTask, Task,
+// UpdateTask and Tasks's date lookup reuse a single planned statement
+// instead of having SQLite re-parse and re-plan the same SQL on every
+// call.
+func (s *Store) prepareStatements() error {
+	var err error
+	if s.addTaskStmt, err = s.db.Preparex(
+		`INSERT INTO scheduler (date, title, comment, repeat, reminder_lead_days, is_habit, repeat_anchor, time, duration_minutes, start_date, pinned, starred, color) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`); err != nil {
+		return fmt.Errorf("prepare add task: %w", err)
+	}
+	if s.taskStmt, err = s.db.Preparex(`SELECT * FROM scheduler WHERE id = ?`); err != nil {
+		return fmt.Errorf("prepare get task: %w", err)
+	}
+	if s.updateTaskStmt, err = s.db.Preparex(
+		`UPDATE scheduler SET date = ?, title = ?, comment = ?, repeat = ?, reminder_lead_days = ?, is_habit = ?, repeat_anchor = ?, time = ?, duration_minutes = ?, start_date = ?, pinned = ?, starred = ?, color = ? WHERE id = ?`); err != nil {
+		return fmt.Errorf("prepare update task: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying database handle and the prepared
+// statements held against it.
+func (s *Store) Close() error {
+	for _, stmt := range []*sqlx.Stmt{s.addTaskStmt, s.taskStmt, s.updateTaskStmt} {
+		stmt.Close()
+	}
+	return s.db.Close()
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling
+// back otherwise. Transactions are serialized via writeMu: SQLite only
+// ever grants one writer at a time, so queuing them here keeps
+// concurrent writers waiting in Go rather than contending for that lock
+// and racing against Options.BusyTimeout. The whole attempt is retried
+// under retryBusy, since a SQLITE_BUSY/SQLITE_LOCKED error can surface
+// from BeginTxx or the commit as easily as from fn itself.
+func (s *Store) withTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	return s.retryBusy(ctx, func() error {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+
+		tx, err := s.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+		if err := fn(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// execWrite runs a single write statement under writeMu, the
+// non-transactional counterpart to withTx for the many single-statement
+// inserts, updates and deletes elsewhere in this package.
+func (s *Store) execWrite(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	err := s.retryBusy(ctx, func() error {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+		var err error
+		result, err = s.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// retryBusy runs fn, retrying it with jittered exponential backoff while
+// it fails with SQLITE_BUSY or SQLITE_LOCKED, until s.writeRetryDeadline
+// elapses. This is on top of the per-statement wait Options.BusyTimeout
+// already grants inside SQLite itself, covering contention that
+// outlasts it - e.g. a backup or CLI import holding the write lock
+// across several of SQLite's own busy-timeout windows. A
+// writeRetryDeadline of zero disables retrying, running fn exactly once.
+func (s *Store) retryBusy(ctx context.Context, fn func() error) error {
+	if s.writeRetryDeadline <= 0 {
+		return fn()
+	}
+
+	deadline := time.Now().Add(s.writeRetryDeadline)
+	backoff := retryBusyBaseDelay
+	for {
+		err := fn()
+		if err == nil || !isBusyOrLockedErr(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2+1)))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > retryBusyMaxDelay {
+			backoff = retryBusyMaxDelay
+		}
+	}
+}
+
+// retryBusyBaseDelay and retryBusyMaxDelay bound the exponential backoff
+// retryBusy applies between attempts.
+const (
+	retryBusyBaseDelay = 20 * time.Millisecond
+	retryBusyMaxDelay  = 500 * time.Millisecond
+)
+
+func (s *Store) AddTask(ctx context.Context, t storage.Task) (int64, error) {
+	var id int64
+	err := s.instrument("AddTask", []any{t.Date, redacted}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			res, err := tx.StmtxContext(ctx, s.addTaskStmt).ExecContext(ctx,
+				t.Date, t.Title, t.Comment, t.Repeat, t.ReminderLeadDays, t.IsHabit, t.RepeatAnchor, t.Time, t.DurationMinutes, t.StartDate, t.Pinned, t.Starred, t.Color)
+			if err != nil {
+				return fmt.Errorf("insert task: %w", err)
+			}
+			id, err = res.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("last insert id: %w", err)
+			}
+			t.ID = id
+			return enqueueOutbox(ctx, tx, events.TaskCreated, t)
+		})
+	})
+	return id, err
+}
+
+// ImportTasks adds every task in tasks within a single transaction, so a
+// bulk import either lands in full or leaves the task list untouched.
+func (s *Store) ImportTasks(ctx context.Context, tasks []storage.Task) ([]int64, error) {
+	ids := make([]int64, len(tasks))
+	err := s.instrument("ImportTasks", []any{len(tasks)}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			for i, t := range tasks {
+				res, err := tx.ExecContext(ctx,
+					`INSERT INTO scheduler (date, title, comment, repeat, reminder_lead_days, is_habit, repeat_anchor, time, duration_minutes, start_date, pinned, starred, color) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+					t.Date, t.Title, t.Comment, t.Repeat, t.ReminderLeadDays, t.IsHabit, t.RepeatAnchor, t.Time, t.DurationMinutes, t.StartDate, t.Pinned, t.Starred, t.Color)
+				if err != nil {
+					return fmt.Errorf("insert task %d: %w", i+1, err)
+				}
+				id, err := res.LastInsertId()
+				if err != nil {
+					return fmt.Errorf("last insert id: %w", err)
+				}
+				t.ID = id
+				ids[i] = id
+				if err := enqueueOutbox(ctx, tx, events.TaskCreated, t); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// RestoreTasks writes every task in tasks within a single transaction,
+// preserving each task's ID rather than assigning a new one; an existing
+// row with the same ID is overwritten.
+func (s *Store) RestoreTasks(ctx context.Context, tasks []storage.Task) error {
+	return s.instrument("RestoreTasks", []any{len(tasks)}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			for i, t := range tasks {
+				_, err := tx.ExecContext(ctx,
+					`INSERT OR REPLACE INTO scheduler (id, date, title, comment, repeat, reminder_lead_days, is_habit, repeat_anchor, time, duration_minutes, start_date, pinned, starred, color) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+					t.ID, t.Date, t.Title, t.Comment, t.Repeat, t.ReminderLeadDays, t.IsHabit, t.RepeatAnchor, t.Time, t.DurationMinutes, t.StartDate, t.Pinned, t.Starred, t.Color)
+				if err != nil {
+					return fmt.Errorf("restore task %d: %w", i+1, err)
+				}
+				if err := enqueueOutbox(ctx, tx, events.TaskCreated, t); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// RescheduleTasks writes every task in tasks within a single
+// transaction, the bulk equivalent of UpdateTask - see
+// api.handleBulkReschedule.
+func (s *Store) RescheduleTasks(ctx context.Context, tasks []storage.Task) error {
+	return s.instrument("RescheduleTasks", []any{len(tasks)}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			for i, t := range tasks {
+				res, err := tx.StmtxContext(ctx, s.updateTaskStmt).ExecContext(ctx,
+					t.Date, t.Title, t.Comment, t.Repeat, t.ReminderLeadDays, t.IsHabit, t.RepeatAnchor, t.Time, t.DurationMinutes, t.StartDate, t.Pinned, t.Starred, t.Color, t.ID)
+				if err != nil {
+					return fmt.Errorf("reschedule task %d: %w", i+1, err)
+				}
+				if err := checkAffected(res); err != nil {
+					return err
+				}
+				if err := enqueueOutbox(ctx, tx, events.TaskUpdated, t); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// Tasks queries the scheduler table, combining search with the
+// starredOnly, color, recurring and repeatPrefix filters as AND
+// conditions - see storage.Store's doc comment for their semantics.
+func (s *Store) Tasks(ctx context.Context, search string, limit int, starredOnly bool, color storage.TaskColor, recurring *bool, repeatPrefix string) ([]storage.Task, error) {
+	if limit <= 0 {
+		limit = storage.DefaultTasksLimit
+	}
+	var tasks []storage.Task
+	err := s.instrument("Tasks", []any{redacted, limit, starredOnly, color, recurring, repeatPrefix}, func() error {
+		var conds []string
+		var args []any
+		switch {
+		case search == "":
+		case isDateQuery(search):
+			iso := toISODate(search)
+			conds = append(conds, "(date = ? OR start_date = ?)")
+			args = append(args, iso, iso)
+		default:
+			like := "%" + search + "%"
+			conds = append(conds, "(title LIKE ? OR comment LIKE ?)")
+			args = append(args, like, like)
+		}
+		if starredOnly {
+			conds = append(conds, "starred = 1")
+		}
+		if color != "" {
+			conds = append(conds, "color = ?")
+			args = append(args, string(color))
+		}
+		if recurring != nil {
+			if *recurring {
+				conds = append(conds, "repeat != ''")
+			} else {
+				conds = append(conds, "repeat = ''")
+			}
+		}
+		if repeatPrefix != "" {
+			// "y" (yearly) takes no arguments and so is stored as exactly
+			// "y" with no trailing " N", unlike "d"/"w"/"m" - match that
+			// case exactly instead of missing it via the LIKE pattern below.
+			conds = append(conds, "(repeat = ? OR repeat LIKE ?)")
+			args = append(args, repeatPrefix, repeatPrefix+" %")
+		}
+
+		query := "SELECT * FROM scheduler"
+		if len(conds) > 0 {
+			query += " WHERE " + strings.Join(conds, " AND ")
+		}
+		query += " ORDER BY pinned DESC, date LIMIT ?"
+		args = append(args, limit)
+
+		rows, err := s.db.QueryxContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("query tasks: %w", err)
+		}
+		defer rows.Close()
+
+		tasks = []storage.Task{}
+		for rows.Next() {
+			var t storage.Task
+			if err := rows.StructScan(&t); err != nil {
+				return fmt.Errorf("scan task: %w", err)
+			}
+			tasks = append(tasks, t)
+		}
+		return rows.Err()
+	})
+	return tasks, err
+}
+
+// countRow mirrors the columns of the aggregate COUNT query CountTasks
+// runs, one alias per storage.TaskCounts field.
+type countRow struct {
+	Total    int `db:"total"`
+	Overdue  int `db:"overdue"`
+	DueToday int `db:"due_today"`
+}
+
+func (s *Store) CountTasks(ctx context.Context, search, today string) (storage.TaskCounts, error) {
+	var counts storage.TaskCounts
+	err := s.instrument("CountTasks", []any{redacted, today}, func() error {
+		const aggregates = `COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE date < ?) AS overdue,
+			COUNT(*) FILTER (WHERE date = ?) AS due_today`
+
+		var row countRow
+		var err error
+		switch {
+		case search == "":
+			err = s.db.QueryRowxContext(ctx,
+				`SELECT `+aggregates+` FROM scheduler`,
+				today, today).StructScan(&row)
+		case isDateQuery(search):
+			iso := toISODate(search)
+			err = s.db.QueryRowxContext(ctx,
+				`SELECT `+aggregates+` FROM scheduler WHERE date = ? OR start_date = ?`,
+				today, today, iso, iso).StructScan(&row)
+		default:
+			like := "%" + search + "%"
+			err = s.db.QueryRowxContext(ctx,
+				`SELECT `+aggregates+` FROM scheduler WHERE title LIKE ? OR comment LIKE ?`,
+				today, today, like, like).StructScan(&row)
+		}
+		if err != nil {
+			return fmt.Errorf("count tasks: %w", err)
+		}
+		counts = storage.TaskCounts{Total: row.Total, Overdue: row.Overdue, DueToday: row.DueToday}
+		return nil
+	})
+	return counts, err
+}
+
+func (s *Store) TasksDueBy(ctx context.Context, date string) ([]storage.Task, error) {
+	var tasks []storage.Task
+	err := s.instrument("TasksDueBy", []any{date}, func() error {
+		rows, err := s.db.QueryxContext(ctx,
+			`SELECT * FROM scheduler WHERE date <= ? ORDER BY date`, date)
+		if err != nil {
+			return fmt.Errorf("query tasks due by %s: %w", date, err)
+		}
+		defer rows.Close()
+
+		tasks = []storage.Task{}
+		for rows.Next() {
+			var t storage.Task
+			if err := rows.StructScan(&t); err != nil {
+				return fmt.Errorf("scan task: %w", err)
+			}
+			tasks = append(tasks, t)
+		}
+		return rows.Err()
+	})
+	return tasks, err
+}
+
+func (s *Store) TasksInRange(ctx context.Context, from, to string) ([]storage.Task, error) {
+	var tasks []storage.Task
+	err := s.instrument("TasksInRange", []any{from, to}, func() error {
+		rows, err := s.db.QueryxContext(ctx,
+			`SELECT * FROM scheduler WHERE date BETWEEN ? AND ? ORDER BY date`, from, to)
+		if err != nil {
+			return fmt.Errorf("query tasks between %s and %s: %w", from, to, err)
+		}
+		defer rows.Close()
+
+		tasks = []storage.Task{}
+		for rows.Next() {
+			var t storage.Task
+			if err := rows.StructScan(&t); err != nil {
+				return fmt.Errorf("scan task: %w", err)
+			}
+			tasks = append(tasks, t)
+		}
+		return rows.Err()
+	})
+	return tasks, err
+}
+
+// TasksStartableBy returns every task with a StartDate set to date or
+// earlier, the "can start now" view.
+func (s *Store) TasksStartableBy(ctx context.Context, date string) ([]storage.Task, error) {
+	var tasks []storage.Task
+	err := s.instrument("TasksStartableBy", []any{date}, func() error {
+		rows, err := s.db.QueryxContext(ctx,
+			`SELECT * FROM scheduler WHERE start_date != '' AND start_date <= ? ORDER BY start_date`, date)
+		if err != nil {
+			return fmt.Errorf("query tasks startable by %s: %w", date, err)
+		}
+		defer rows.Close()
+
+		tasks = []storage.Task{}
+		for rows.Next() {
+			var t storage.Task
+			if err := rows.StructScan(&t); err != nil {
+				return fmt.Errorf("scan task: %w", err)
+			}
+			tasks = append(tasks, t)
+		}
+		return rows.Err()
+	})
+	return tasks, err
+}
+
+// StreamTasks scans every task row-by-row, calling fn on each without ever
+// holding more than one in memory, so an export of a very large task list
+// doesn't need to buffer it all first.
+func (s *Store) StreamTasks(ctx context.Context, fn func(storage.Task) error) error {
+	return s.instrument("StreamTasks", nil, func() error {
+		rows, err := s.db.QueryxContext(ctx, `SELECT * FROM scheduler ORDER BY date`)
+		if err != nil {
+			return fmt.Errorf("query tasks: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t storage.Task
+			if err := rows.StructScan(&t); err != nil {
+				return fmt.Errorf("scan task: %w", err)
+			}
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
+
+func (s *Store) Task(ctx context.Context, id int64) (storage.Task, error) {
+	var t storage.Task
+	err := s.instrument("Task", []any{id}, func() error {
+		err := s.taskStmt.GetContext(ctx, &t, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("get task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return storage.Task{}, err
+	}
+	return t, nil
+}
+
+func (s *Store) UpdateTask(ctx context.Context, t storage.Task) error {
+	return s.instrument("UpdateTask", []any{t.ID, t.Date, redacted}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			res, err := tx.StmtxContext(ctx, s.updateTaskStmt).ExecContext(ctx,
+				t.Date, t.Title, t.Comment, t.Repeat, t.ReminderLeadDays, t.IsHabit, t.RepeatAnchor, t.Time, t.DurationMinutes, t.StartDate, t.Pinned, t.Starred, t.Color, t.ID)
+			if err != nil {
+				return fmt.Errorf("update task: %w", err)
+			}
+			if err := checkAffected(res); err != nil {
+				return err
+			}
+			return enqueueOutbox(ctx, tx, events.TaskUpdated, t)
+		})
+	})
+}
+
+// DeleteTask moves id to the trash table, stamped with the current time,
+// then removes it from scheduler - it disappears from every normal query
+// immediately, but internal/retention's trash purge doesn't permanently
+// remove it until TrashRetentionAge has passed, giving a window to
+// notice an accidental delete.
+func (s *Store) DeleteTask(ctx context.Context, id int64) error {
+	return s.instrument("DeleteTask", []any{id}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO trash (task_id, date, title, comment, repeat, reminder_lead_days, is_habit, repeat_anchor, time, duration_minutes, start_date, pinned, starred, color, trashed_at)
+				SELECT id, date, title, comment, repeat, reminder_lead_days, is_habit, repeat_anchor, time, duration_minutes, start_date, pinned, starred, color, ?
+				FROM scheduler WHERE id = ?`, time.Now().UTC(), id)
+			if err != nil {
+				return fmt.Errorf("trash task: %w", err)
+			}
+			if err := checkAffected(res); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM scheduler WHERE id = ?`, id); err != nil {
+				return fmt.Errorf("delete task: %w", err)
+			}
+			return enqueueOutbox(ctx, tx, events.TaskDeleted, storage.Task{ID: id})
+		})
+	})
+}
+
+// CompleteTask marks t done within a single transaction: it either deletes
+// the task (next == "") or reschedules it to next, and records the
+// resulting TaskCompleted event alongside the mutation so the two can never
+// diverge across a crash.
+func (s *Store) CompleteTask(ctx context.Context, t storage.Task, next string) error {
+	return s.instrument("CompleteTask", []any{t.ID, next}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			if next == "" {
+				res, err := tx.ExecContext(ctx, `DELETE FROM scheduler WHERE id = ?`, t.ID)
+				if err != nil {
+					return fmt.Errorf("delete task: %w", err)
+				}
+				if err := checkAffected(res); err != nil {
+					return err
+				}
+			} else {
+				res, err := tx.ExecContext(ctx,
+					`UPDATE scheduler SET date = ?, repeat_anchor = ? WHERE id = ?`, next, next, t.ID)
+				if err != nil {
+					return fmt.Errorf("reschedule task: %w", err)
+				}
+				if err := checkAffected(res); err != nil {
+					return err
+				}
+			}
+			return enqueueOutbox(ctx, tx, events.TaskCompleted, t)
+		})
+	})
+}
+
+// SkipTask advances t to next without recording a completion, publishing
+// a TaskSkipped event instead of the TaskCompleted CompleteTask records.
+func (s *Store) SkipTask(ctx context.Context, t storage.Task, next string) error {
+	return s.instrument("SkipTask", []any{t.ID, next}, func() error {
+		return s.withTx(ctx, func(tx *sqlx.Tx) error {
+			res, err := tx.ExecContext(ctx,
+				`UPDATE scheduler SET date = ?, repeat_anchor = ? WHERE id = ?`, next, next, t.ID)
+			if err != nil {
+				return fmt.Errorf("reschedule task: %w", err)
+			}
+			if err := checkAffected(res); err != nil {
+				return err
+			}
+			return enqueueOutbox(ctx, tx, events.TaskSkipped, t)
+		})
+	})
+}
+
+func checkAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func isDateQuery(s string) bool {
+	_, err := time.Parse(dateQueryLayout, s)
+	return err == nil
+}
+
+func toISODate(s string) string {
+	t, _ := time.Parse(dateQueryLayout, s)
+	return t.Format("20060102")
+}
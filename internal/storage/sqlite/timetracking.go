@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/nextdate"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// StartTimer opens a new TimeEntry for taskID, returning
+// storage.ErrTimerRunning if one is already open for it.
+func (s *Store) StartTimer(ctx context.Context, taskID int64, startedAt time.Time) (storage.TimeEntry, error) {
+	var entry storage.TimeEntry
+	err := s.withTx(ctx, func(tx *sqlx.Tx) error {
+		var running int
+		err := tx.GetContext(ctx, &running,
+			`SELECT 1 FROM time_entries WHERE task_id = ? AND stopped_at IS NULL`, taskID)
+		if err == nil {
+			return storage.ErrTimerRunning
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("check running timer: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO time_entries (task_id, started_at, stopped_at) VALUES (?, ?, NULL)`,
+			taskID, startedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("start timer: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("start timer: %w", err)
+		}
+		entry = storage.TimeEntry{ID: id, TaskID: taskID, StartedAt: startedAt.UTC()}
+		return nil
+	})
+	return entry, err
+}
+
+// StopTimer closes taskID's open TimeEntry as of stoppedAt, returning
+// storage.ErrTimerNotRunning if none is open.
+func (s *Store) StopTimer(ctx context.Context, taskID int64, stoppedAt time.Time) (storage.TimeEntry, error) {
+	var entry storage.TimeEntry
+	err := s.withTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(ctx,
+			`SELECT id, task_id, started_at, stopped_at FROM time_entries WHERE task_id = ? AND stopped_at IS NULL`,
+			taskID).Scan(&entry.ID, &entry.TaskID, &entry.StartedAt, &entry.StoppedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrTimerNotRunning
+		}
+		if err != nil {
+			return fmt.Errorf("find running timer: %w", err)
+		}
+
+		stopped := stoppedAt.UTC()
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE time_entries SET stopped_at = ? WHERE id = ?`, stopped, entry.ID); err != nil {
+			return fmt.Errorf("stop timer: %w", err)
+		}
+		entry.StoppedAt = &stopped
+		return nil
+	})
+	return entry, err
+}
+
+// TimeEntriesForTask returns every entry logged against taskID, oldest
+// first, including an open one if there is one.
+func (s *Store) TimeEntriesForTask(ctx context.Context, taskID int64) ([]storage.TimeEntry, error) {
+	entries := []storage.TimeEntry{}
+	err := s.db.SelectContext(ctx, &entries,
+		`SELECT id, task_id, started_at, stopped_at FROM time_entries WHERE task_id = ? ORDER BY started_at`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("list time entries for task %d: %w", taskID, err)
+	}
+	return entries, nil
+}
+
+// TimeEntriesOnDate returns every entry that started on date (YYYYMMDD),
+// for the per-day report.
+func (s *Store) TimeEntriesOnDate(ctx context.Context, date string) ([]storage.TimeEntry, error) {
+	day, err := nextdate.Parse(date)
+	if err != nil {
+		return nil, fmt.Errorf("parse date %q: %w", date, err)
+	}
+
+	entries := []storage.TimeEntry{}
+	err = s.db.SelectContext(ctx, &entries,
+		`SELECT id, task_id, started_at, stopped_at FROM time_entries WHERE started_at >= ? AND started_at < ? ORDER BY started_at`,
+		day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("list time entries on %s: %w", date, err)
+	}
+	return entries, nil
+}
+
+// AllTimeEntries returns every recorded entry, for a full-dataset export.
+func (s *Store) AllTimeEntries(ctx context.Context) ([]storage.TimeEntry, error) {
+	entries := []storage.TimeEntry{}
+	err := s.db.SelectContext(ctx, &entries,
+		`SELECT id, task_id, started_at, stopped_at FROM time_entries ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list time entries: %w", err)
+	}
+	return entries, nil
+}
+
+// RestoreTimeEntries re-records every entry in entries, preserving each
+// one's ID.
+func (s *Store) RestoreTimeEntries(ctx context.Context, entries []storage.TimeEntry) error {
+	return s.withTx(ctx, func(tx *sqlx.Tx) error {
+		for _, e := range entries {
+			_, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO time_entries (id, task_id, started_at, stopped_at) VALUES (?, ?, ?, ?)`,
+				e.ID, e.TaskID, e.StartedAt, e.StoppedAt)
+			if err != nil {
+				return fmt.Errorf("restore time entry %d: %w", e.ID, err)
+			}
+		}
+		return nil
+	})
+}
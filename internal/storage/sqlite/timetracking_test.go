@@ -0,0 +1,127 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestTimerStartStop(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "focus work"})
+	require.NoError(t, err)
+
+	_, err = store.StopTimer(ctx, id, time.Now())
+	assert.ErrorIs(t, err, storage.ErrTimerNotRunning)
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	entry, err := store.StartTimer(ctx, id, start)
+	require.NoError(t, err)
+	assert.True(t, entry.Running())
+
+	_, err = store.StartTimer(ctx, id, start)
+	assert.ErrorIs(t, err, storage.ErrTimerRunning)
+
+	stop := start.Add(30 * time.Minute)
+	entry, err = store.StopTimer(ctx, id, stop)
+	require.NoError(t, err)
+	assert.False(t, entry.Running())
+	assert.Equal(t, 30, entry.Minutes(time.Now()))
+
+	// The timer is closed now, so starting a new one for the same task
+	// must succeed rather than returning ErrTimerRunning.
+	_, err = store.StartTimer(ctx, id, stop.Add(time.Hour))
+	require.NoError(t, err)
+}
+
+func TestTimeEntriesForTaskAndDate(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "focus work"})
+	require.NoError(t, err)
+
+	jan1 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	_, err = store.StartTimer(ctx, id, jan1)
+	require.NoError(t, err)
+	_, err = store.StopTimer(ctx, id, jan1.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = store.StartTimer(ctx, id, jan2)
+	require.NoError(t, err)
+	_, err = store.StopTimer(ctx, id, jan2.Add(time.Hour))
+	require.NoError(t, err)
+
+	forTask, err := store.TimeEntriesForTask(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, forTask, 2)
+
+	onJan1, err := store.TimeEntriesOnDate(ctx, "20260101")
+	require.NoError(t, err)
+	require.Len(t, onJan1, 1)
+	assert.True(t, onJan1[0].StartedAt.Equal(jan1))
+}
+
+func TestAllTimeEntries(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	taskA, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task a"})
+	require.NoError(t, err)
+	taskB, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task b"})
+	require.NoError(t, err)
+
+	all, err := store.AllTimeEntries(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	_, err = store.StartTimer(ctx, taskA, start)
+	require.NoError(t, err)
+	_, err = store.StartTimer(ctx, taskB, start)
+	require.NoError(t, err)
+
+	// AllTimeEntries is a full-dataset export, so it must return open
+	// timers alongside closed ones, unlike TimeEntriesForTask filtering
+	// by a single task.
+	all, err = store.AllTimeEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	for _, e := range all {
+		assert.True(t, e.Running())
+	}
+}
+
+func TestRestoreTimeEntries(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "focus work"})
+	require.NoError(t, err)
+
+	stopped := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []storage.TimeEntry{
+		{ID: 42, TaskID: id, StartedAt: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), StoppedAt: &stopped},
+	}
+	require.NoError(t, store.RestoreTimeEntries(ctx, entries))
+
+	got, err := store.TimeEntriesForTask(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(42), got[0].ID)
+
+	// Restoring the same entries again must not duplicate or error
+	// (INSERT OR IGNORE), the same guarantee RestoreSentReminders makes.
+	require.NoError(t, store.RestoreTimeEntries(ctx, entries))
+	got, err = store.TimeEntriesForTask(ctx, id)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func (s *Store) SaveRefreshToken(ctx context.Context, hash string, expiresAt time.Time, subject string, permission storage.Permission) error {
+	_, err := s.execWrite(ctx,
+		`INSERT INTO refresh_tokens (hash, expires_at, subject, permission) VALUES (?, ?, ?, ?)`,
+		hash, expiresAt, subject, permission)
+	if err != nil {
+		return fmt.Errorf("save refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RefreshToken(ctx context.Context, hash string) (storage.RefreshToken, error) {
+	var t storage.RefreshToken
+	err := s.db.QueryRowxContext(ctx,
+		`SELECT hash, expires_at, subject, permission FROM refresh_tokens WHERE hash = ?`, hash).
+		Scan(&t.Hash, &t.ExpiresAt, &t.Subject, &t.Permission)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.RefreshToken{}, storage.ErrTokenNotFound
+	}
+	if err != nil {
+		return storage.RefreshToken{}, fmt.Errorf("get refresh token: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Store) DeleteRefreshToken(ctx context.Context, hash string) error {
+	if _, err := s.execWrite(ctx, `DELETE FROM refresh_tokens WHERE hash = ?`, hash); err != nil {
+		return fmt.Errorf("delete refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteRefreshTokensBySubject(ctx context.Context, subject string) error {
+	if _, err := s.execWrite(ctx, `DELETE FROM refresh_tokens WHERE subject = ?`, subject); err != nil {
+		return fmt.Errorf("delete refresh tokens by subject: %w", err)
+	}
+	return nil
+}
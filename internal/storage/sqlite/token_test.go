@@ -0,0 +1,56 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestRefreshTokenLifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	_, err := store.RefreshToken(ctx, "unknown-hash")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, store.SaveRefreshToken(ctx, "hash-1", expiresAt, "alice", storage.PermissionWrite))
+
+	got, err := store.RefreshToken(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "hash-1", got.Hash)
+	assert.Equal(t, "alice", got.Subject)
+	assert.Equal(t, storage.PermissionWrite, got.Permission)
+	assert.WithinDuration(t, expiresAt, got.ExpiresAt, time.Second)
+
+	require.NoError(t, store.DeleteRefreshToken(ctx, "hash-1"))
+	_, err = store.RefreshToken(ctx, "hash-1")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func TestDeleteRefreshTokensBySubject(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, store.SaveRefreshToken(ctx, "alice-1", expiresAt, "alice", storage.PermissionWrite))
+	require.NoError(t, store.SaveRefreshToken(ctx, "alice-2", expiresAt, "alice", storage.PermissionWrite))
+	require.NoError(t, store.SaveRefreshToken(ctx, "bob-1", expiresAt, "bob", storage.PermissionRead))
+
+	require.NoError(t, store.DeleteRefreshTokensBySubject(ctx, "alice"))
+
+	_, err := store.RefreshToken(ctx, "alice-1")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+	_, err = store.RefreshToken(ctx, "alice-2")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+
+	// bob's token, a different subject, must be untouched.
+	got, err := store.RefreshToken(ctx, "bob-1")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", got.Subject)
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CountTrashedTasksBefore reports how many trashed tasks were deleted
+// before cutoff.
+func (s *Store) CountTrashedTasksBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	var count int
+	if err := s.db.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM trash WHERE trashed_at < ?`, cutoff.UTC()); err != nil {
+		return 0, fmt.Errorf("count trashed tasks before %s: %w", cutoff, err)
+	}
+	return count, nil
+}
+
+// DeleteTrashedTasksBefore permanently removes trashed tasks deleted
+// before cutoff and returns how many were removed.
+func (s *Store) DeleteTrashedTasksBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	res, err := s.execWrite(ctx, `DELETE FROM trash WHERE trashed_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete trashed tasks before %s: %w", cutoff, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return int(n), nil
+}
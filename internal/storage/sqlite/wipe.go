@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// WipeAllData deletes every row from scheduler and the tables that
+// reference a task by ID, along with every collaborator and Google
+// Tasks mapping tied to this account, and revokes every outstanding
+// refresh token - the owner's and every collaborator's alike - all
+// within a single transaction, so a crash midway never leaves orphaned
+// history, credentials or sessions behind. It enqueues a TaskDeleted
+// event per removed task, the same as DeleteTask, so caches sitting in
+// front of the store invalidate once the outbox dispatcher delivers
+// them.
+func (s *Store) WipeAllData(ctx context.Context) (storage.WipeReport, error) {
+	var report storage.WipeReport
+	err := s.withTx(ctx, func(tx *sqlx.Tx) error {
+		var ids []int64
+		if err := tx.SelectContext(ctx, &ids, `SELECT id FROM scheduler`); err != nil {
+			return fmt.Errorf("list task ids: %w", err)
+		}
+
+		counts := []struct {
+			query string
+			dest  *int
+		}{
+			{`DELETE FROM sent_reminders`, &report.SentReminders},
+			{`DELETE FROM time_entries`, &report.TimeEntries},
+			{`DELETE FROM task_notes`, &report.Notes},
+			{`DELETE FROM task_links`, &report.Links},
+			{`DELETE FROM scheduler`, &report.Tasks},
+			{`DELETE FROM collaborators`, &report.Collaborators},
+			{`DELETE FROM google_task_mappings`, &report.GoogleMappings},
+			{`DELETE FROM refresh_tokens`, &report.RefreshRevoked},
+			{`DELETE FROM trash`, &report.TrashedTasks},
+		}
+		for _, c := range counts {
+			res, err := tx.ExecContext(ctx, c.query)
+			if err != nil {
+				return fmt.Errorf("wipe data: %w", err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("rows affected: %w", err)
+			}
+			*c.dest = int(n)
+		}
+
+		for _, id := range ids {
+			if err := enqueueOutbox(ctx, tx, events.TaskDeleted, storage.Task{ID: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return storage.WipeReport{}, err
+	}
+	return report, nil
+}
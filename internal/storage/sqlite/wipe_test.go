@@ -0,0 +1,72 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+func TestWipeAllData(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	taskID, err := store.AddTask(ctx, storage.Task{Date: "20260101", Title: "task"})
+	require.NoError(t, err)
+	require.NoError(t, store.MarkReminderSent(ctx, taskID, "20260101"))
+	_, err = store.StartTimer(ctx, taskID, time.Now())
+	require.NoError(t, err)
+	_, err = store.AddNote(ctx, taskID, "note", time.Now())
+	require.NoError(t, err)
+	otherID, err := store.AddTask(ctx, storage.Task{Date: "20260102", Title: "other"})
+	require.NoError(t, err)
+	_, err = store.AddLink(ctx, taskID, otherID, storage.LinkRelated, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, store.UpsertGoogleMapping(ctx, storage.GoogleTaskMapping{TaskID: taskID, GoogleTaskID: "g-1"}))
+
+	_, err = store.CreateCollaborator(ctx, "carol", "hash", storage.PermissionWrite)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveRefreshToken(ctx, "owner-token", time.Now().Add(time.Hour), "", storage.PermissionWrite))
+	require.NoError(t, store.SaveRefreshToken(ctx, "carol-token", time.Now().Add(time.Hour), "carol", storage.PermissionWrite))
+
+	report, err := store.WipeAllData(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Tasks)
+	assert.Equal(t, 1, report.SentReminders)
+	assert.Equal(t, 1, report.TimeEntries)
+	assert.Equal(t, 1, report.Notes)
+	assert.Equal(t, 1, report.Links)
+	assert.Equal(t, 1, report.Collaborators)
+	assert.Equal(t, 1, report.GoogleMappings)
+	assert.Equal(t, 2, report.RefreshRevoked)
+
+	// Every dependent table and the collaborator's own credentials must
+	// be gone, not just the tasks.
+	tasks, err := store.Tasks(ctx, "", 10, false, "", nil, "")
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	_, err = store.CollaboratorByUsername(ctx, "carol")
+	assert.ErrorIs(t, err, storage.ErrCollaboratorNotFound)
+
+	_, err = store.RefreshToken(ctx, "owner-token")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+	_, err = store.RefreshToken(ctx, "carol-token")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+
+	mappings, err := store.GoogleMappings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, mappings)
+
+	// A wipe on an already-empty database is a well-formed no-op, not an
+	// error.
+	report, err = store.WipeAllData(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, storage.WipeReport{}, report)
+}
@@ -0,0 +1,153 @@
+// Package storage defines the scheduler's persistence contract. Concrete
+// backends live in sibling packages, such as storage/sqlite.
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a task lookup, update or delete targets an
+// id that does not exist.
+var ErrNotFound = errors.New("task not found")
+
+// DefaultTasksLimit is the number of rows Tasks returns when called with
+// limit <= 0.
+const DefaultTasksLimit = 50
+
+// Task is a single scheduler entry.
+type Task struct {
+	ID      int64  `db:"id" json:"id,string" xml:"id"`
+	Date    string `db:"date" json:"date" xml:"date"`
+	Title   string `db:"title" json:"title" xml:"title"`
+	Comment string `db:"comment" json:"comment" xml:"comment"`
+	Repeat  string `db:"repeat" json:"repeat" xml:"repeat"`
+
+	// ReminderLeadDays is how many days before Date a reminder should be
+	// sent. 0 (the default) uses the reminder scheduler's configured
+	// default lead time instead of overriding it per task.
+	ReminderLeadDays int `db:"reminder_lead_days" json:"reminder_lead_days,omitempty" xml:"reminder_lead_days,omitempty"`
+
+	// IsHabit marks a recurring task for streak tracking - see
+	// api.handleTaskStreak, which reports consecutive on-time
+	// completions for any task regardless of this flag, but the flag is
+	// what a client uses to decide whether to show that UI at all.
+	IsHabit bool `db:"is_habit" json:"is_habit,omitempty" xml:"is_habit,omitempty"`
+
+	// RepeatAnchor is the date nextdate.Next uses as the start of a
+	// repeating task's schedule, kept separate from Date so that
+	// api.handleSnoozeTask can move Date - the currently due
+	// occurrence - without shifting the phase of future occurrences.
+	// It's empty for non-repeating tasks and normally tracks Date, only
+	// diverging from it while a snooze is in effect.
+	RepeatAnchor string `db:"repeat_anchor" json:"repeat_anchor,omitempty" xml:"repeat_anchor,omitempty"`
+
+	// Time is the task's due time of day, in 24-hour "15:04" form. Empty
+	// means Date alone describes when it's due, with no particular time.
+	Time string `db:"time" json:"time,omitempty" xml:"time,omitempty"`
+
+	// DurationMinutes is how long the task is expected to take, starting
+	// at Time if set. 0 means no estimate was given.
+	DurationMinutes int `db:"duration_minutes" json:"duration_minutes,omitempty" xml:"duration_minutes,omitempty"`
+
+	// StartDate is when a long-running task can be picked up, kept
+	// separate from Date - the due date - so it can appear in a "can
+	// start now" view (see Store.TasksStartableBy) without looking
+	// overdue the way stuffing an early Date in would. Empty means the
+	// task has no start constraint of its own.
+	StartDate string `db:"start_date" json:"start_date,omitempty" xml:"start_date,omitempty"`
+
+	// Pinned marks a task to always sort first in Store.Tasks, regardless
+	// of date, for something the user wants kept at the top of the list.
+	Pinned bool `db:"pinned" json:"pinned,omitempty" xml:"pinned,omitempty"`
+
+	// Starred marks a task as a favorite, toggled independently of
+	// Pinned - see Store.Tasks's starredOnly parameter.
+	Starred bool `db:"starred" json:"starred,omitempty" xml:"starred,omitempty"`
+
+	// Color labels the task for a UI that wants to distinguish
+	// categories at a glance - see TaskColor. Empty means no color was
+	// set.
+	Color TaskColor `db:"color" json:"color,omitempty" xml:"color,omitempty"`
+}
+
+// TaskCounts is aggregate metadata about a set of tasks, for rendering
+// summaries next to a paginated listing without scanning the whole page.
+type TaskCounts struct {
+	Total    int `json:"total"`
+	Overdue  int `json:"overdue"`
+	DueToday int `json:"due_today"`
+}
+
+// Store is the persistence contract the API layer depends on.
+type Store interface {
+	AddTask(ctx context.Context, t Task) (int64, error)
+	// ImportTasks adds every task in tasks within a single transaction,
+	// returning their assigned ids in the same order. Either all of tasks
+	// are added or, on the first error, none are.
+	ImportTasks(ctx context.Context, tasks []Task) ([]int64, error)
+	// RestoreTasks writes every task in tasks within a single transaction,
+	// preserving each task's ID rather than assigning a new one, so
+	// restoring a full archive (see the archive package) leaves other
+	// records that reference a task by ID, such as sent reminder history,
+	// pointing at the right task.
+	RestoreTasks(ctx context.Context, tasks []Task) error
+	// RescheduleTasks updates the date of every task in tasks within a
+	// single transaction, so a bulk reschedule either lands in full or
+	// leaves the task list untouched - see api.handleBulkReschedule. Each
+	// task's other fields, including RepeatAnchor, are written back
+	// exactly as given, so the caller controls whether a recurring
+	// task's schedule anchor moves along with its date.
+	RescheduleTasks(ctx context.Context, tasks []Task) error
+	// Tasks returns tasks matching search (see the isDateQuery/LIKE
+	// semantics in the sqlite implementation), pinned ones first and then
+	// by date, up to limit rows. limit <= 0 is treated as
+	// DefaultTasksLimit rather than "no limit" or "no rows", so a caller
+	// that forgets to set it still gets a sensibly sized page. starredOnly
+	// restricts the result to tasks with Starred set, and color, when
+	// non-empty, to tasks with that exact Color. recurring, when non-nil,
+	// restricts the result to tasks with a non-empty Repeat (true) or an
+	// empty one (false); repeatPrefix, when non-empty, further restricts
+	// it to tasks whose Repeat starts with that unit ("d", "w", "m" or
+	// "y", matching the nextdate repeat-rule syntax).
+	Tasks(ctx context.Context, search string, limit int, starredOnly bool, color TaskColor, recurring *bool, repeatPrefix string) ([]Task, error)
+	// CountTasks returns aggregate counts for every task matching search,
+	// using the same search semantics as Tasks, computed with COUNT
+	// queries rather than by loading and scanning the matching rows.
+	CountTasks(ctx context.Context, search, today string) (TaskCounts, error)
+	// TasksDueBy returns every task whose date is on or before date,
+	// ordered by date ascending so the most overdue tasks come first.
+	TasksDueBy(ctx context.Context, date string) ([]Task, error)
+	// TasksInRange returns every task whose date falls between from and
+	// to inclusive, ordered by date ascending. It only matches a task's
+	// own stored date - projecting a recurring task's future occurrences
+	// into the range is the caller's job, since that depends on the
+	// nextdate package rather than the store.
+	TasksInRange(ctx context.Context, from, to string) ([]Task, error)
+	// TasksStartableBy returns every task whose StartDate is set and on
+	// or before date, ordered by start date ascending - the "can start
+	// now" view. A task with no StartDate never appears here regardless
+	// of date, since it has no start constraint to satisfy.
+	TasksStartableBy(ctx context.Context, date string) ([]Task, error)
+	// StreamTasks calls fn for every task, ordered by date, without
+	// loading them all into memory at once - for exports of datasets too
+	// large to hold in a single slice. It stops and returns fn's error as
+	// soon as fn returns one.
+	StreamTasks(ctx context.Context, fn func(Task) error) error
+	Task(ctx context.Context, id int64) (Task, error)
+	UpdateTask(ctx context.Context, t Task) error
+	// DeleteTask moves id to trash: it disappears from every other
+	// method on this interface immediately, but isn't permanently
+	// removed until internal/retention's trash purge catches up with it
+	// under RetentionStore's TrashRetentionAge cutoff.
+	DeleteTask(ctx context.Context, id int64) error
+	// CompleteTask marks t done, atomically deleting it if next is empty or
+	// rescheduling it to next otherwise. The implementation is responsible
+	// for recording the resulting completion event alongside the mutation.
+	CompleteTask(ctx context.Context, t Task, next string) error
+	// SkipTask reschedules t to next without recording a completion - see
+	// api.handleSkipTask. Unlike CompleteTask, next is never empty:
+	// skipping only applies to recurring tasks, which always have one.
+	SkipTask(ctx context.Context, t Task, next string) error
+	Close() error
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimerRunning is returned by TimeTracker.StartTimer when a task
+// already has an open timer.
+var ErrTimerRunning = errors.New("для задачи уже запущен таймер")
+
+// ErrTimerNotRunning is returned by TimeTracker.StopTimer when a task has
+// no open timer to stop.
+var ErrTimerNotRunning = errors.New("для задачи не запущен таймер")
+
+// TimeEntry is one logged interval of work on a task, opened by
+// TimeTracker.StartTimer and closed by TimeTracker.StopTimer. StoppedAt
+// is nil while the timer is still running.
+type TimeEntry struct {
+	ID        int64      `db:"id" json:"id"`
+	TaskID    int64      `db:"task_id" json:"task_id"`
+	StartedAt time.Time  `db:"started_at" json:"started_at"`
+	StoppedAt *time.Time `db:"stopped_at" json:"stopped_at,omitempty"`
+}
+
+// Running reports whether e is still an open timer.
+func (e TimeEntry) Running() bool {
+	return e.StoppedAt == nil
+}
+
+// Minutes reports how long e has run so far, in whole minutes: the gap
+// between StartedAt and StoppedAt for a closed entry, or between
+// StartedAt and now for one still running.
+func (e TimeEntry) Minutes(now time.Time) int {
+	end := now
+	if e.StoppedAt != nil {
+		end = *e.StoppedAt
+	}
+	return int(end.Sub(e.StartedAt).Minutes())
+}
+
+// TimeTracker records start/stop timers logged against tasks, backing
+// POST /api/task/timer/start and .../stop and the per-day time report.
+// It's a capability alongside the main Store, the same way ReminderStore
+// tracks reminder delivery separately from the task rows themselves.
+type TimeTracker interface {
+	// StartTimer opens a new TimeEntry for taskID, returning
+	// ErrTimerRunning if one is already open for it.
+	StartTimer(ctx context.Context, taskID int64, startedAt time.Time) (TimeEntry, error)
+	// StopTimer closes taskID's open TimeEntry as of stoppedAt, returning
+	// ErrTimerNotRunning if none is open.
+	StopTimer(ctx context.Context, taskID int64, stoppedAt time.Time) (TimeEntry, error)
+	// TimeEntriesForTask returns every entry logged against taskID,
+	// oldest first, including an open one if there is one.
+	TimeEntriesForTask(ctx context.Context, taskID int64) ([]TimeEntry, error)
+	// TimeEntriesOnDate returns every entry that started on date
+	// (YYYYMMDD), for the per-day report.
+	TimeEntriesOnDate(ctx context.Context, date string) ([]TimeEntry, error)
+	// AllTimeEntries returns every recorded entry, for a full-dataset
+	// export.
+	AllTimeEntries(ctx context.Context) ([]TimeEntry, error)
+	// RestoreTimeEntries re-records every entry in entries, preserving
+	// each one's ID.
+	RestoreTimeEntries(ctx context.Context, entries []TimeEntry) error
+}
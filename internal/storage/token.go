@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTokenNotFound is returned when a refresh token lookup finds no
+// matching, unexpired record.
+var ErrTokenNotFound = errors.New("refresh token not found")
+
+// RefreshToken is a persisted refresh token, keyed by the hash of its
+// value so the raw token is never stored at rest. Subject and Permission
+// identify who the matching access token was issued for, so a refresh
+// re-issues the same identity instead of silently upgrading it.
+type RefreshToken struct {
+	Hash       string
+	ExpiresAt  time.Time
+	Subject    string
+	Permission Permission
+}
+
+// RefreshTokenStore persists refresh tokens so they can be looked up and
+// revoked independently of the short-lived access token they mint.
+type RefreshTokenStore interface {
+	SaveRefreshToken(ctx context.Context, hash string, expiresAt time.Time, subject string, permission Permission) error
+	RefreshToken(ctx context.Context, hash string) (RefreshToken, error)
+	DeleteRefreshToken(ctx context.Context, hash string) error
+	// DeleteRefreshTokensBySubject revokes every outstanding refresh token
+	// issued to subject, e.g. when a collaborator's access is withdrawn.
+	DeleteRefreshTokensBySubject(ctx context.Context, subject string) error
+}
+
+// RevokedTokenStore records access token jti's that must be rejected even
+// though their signature and expiry are still valid, e.g. after signout.
+type RevokedTokenStore interface {
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// SessionStore is the combined persistence contract the auth handlers and
+// ValidateTokenMiddleware depend on.
+type SessionStore interface {
+	RefreshTokenStore
+	RevokedTokenStore
+	APITokenStore
+	CollaboratorStore
+}
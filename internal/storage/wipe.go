@@ -0,0 +1,31 @@
+package storage
+
+import "context"
+
+// WipeReport counts what a DataWiper.WipeAllData call removed, so the
+// caller can hand the counts back as a deletion receipt.
+type WipeReport struct {
+	Tasks          int `json:"tasks"`
+	SentReminders  int `json:"sent_reminders"`
+	TimeEntries    int `json:"time_entries"`
+	Notes          int `json:"notes"`
+	Links          int `json:"links"`
+	Collaborators  int `json:"collaborators"`
+	GoogleMappings int `json:"google_task_mappings"`
+	RefreshRevoked int `json:"refresh_tokens_revoked"`
+	TrashedTasks   int `json:"trashed_tasks"`
+}
+
+// DataWiper permanently removes every task and everything that
+// references one - reminder delivery history, logged time entries,
+// notes and links - within a single transaction. It also removes every
+// collaborator the owner has invited (their username and password hash
+// live here, not just an access grant) and any Google Tasks mapping,
+// revokes every outstanding refresh token, the owner's and every
+// collaborator's alike, since a collaborator's access is data about
+// this account too, and empties the trash so nothing DeleteTask moved
+// there survives the wipe waiting out its retention window. It backs
+// the GDPR "delete all my data" endpoint (see api.handleDeleteAllData).
+type DataWiper interface {
+	WipeAllData(ctx context.Context) (WipeReport, error)
+}
@@ -0,0 +1,151 @@
+// Package taskcache implements an optional cache-aside layer in front
+// of a storage.Store's task reads, backed by Redis, for deployments
+// where the same task list or search is requested far more often than
+// the underlying data changes.
+package taskcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ngavrilenkov/go_final_project/internal/events"
+	"github.com/ngavrilenkov/go_final_project/internal/storage"
+)
+
+// generationKey holds a counter bumped on every task mutation event.
+// Every cache key embeds the current generation, so bumping it
+// invalidates every previously cached read at once without having to
+// track and delete each key individually.
+const generationKey = "tasks:generation"
+
+// Store wraps a storage.Store, caching Tasks and Task reads in Redis
+// keyed by their query and the current generation, until ttl elapses
+// or a task mutation event bumps the generation. Every other
+// storage.Store method passes straight through to the wrapped store.
+type Store struct {
+	storage.Store
+	redis       *redis.Client
+	ttl         time.Duration
+	unsubscribe func()
+}
+
+// New wraps inner with a cache-aside layer backed by the Redis server
+// at addr, subscribing to bus so a task mutation invalidates every
+// cached read. Call Close to unsubscribe and close the Redis client;
+// it does not close inner.
+func New(inner storage.Store, addr string, ttl time.Duration, bus *events.Bus) *Store {
+	s := &Store{
+		Store: inner,
+		redis: redis.NewClient(&redis.Options{Addr: addr}),
+		ttl:   ttl,
+	}
+	s.unsubscribe = bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if err := s.redis.Incr(ctx, generationKey).Err(); err != nil {
+			log.Printf("taskcache: invalidate: %v", err)
+		}
+	})
+	return s
+}
+
+// Tasks serves search from the cache when present, falling back to and
+// then repopulating from the wrapped store on a miss or a Redis error.
+func (s *Store) Tasks(ctx context.Context, search string, limit int, starredOnly bool, color storage.TaskColor, recurring *bool, repeatPrefix string) ([]storage.Task, error) {
+	key, ok := s.key(ctx, "list", fmt.Sprintf("%d:%s:%t:%s:%s:%s", limit, search, starredOnly, color, recurringKey(recurring), repeatPrefix))
+	if ok {
+		var tasks []storage.Task
+		if s.get(ctx, key, &tasks) {
+			return tasks, nil
+		}
+	}
+
+	tasks, err := s.Store.Tasks(ctx, search, limit, starredOnly, color, recurring, repeatPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		s.set(ctx, key, tasks)
+	}
+	return tasks, nil
+}
+
+// recurringKey renders recurring for use in a cache key: dereferencing it
+// rather than formatting the pointer itself, whose address is meaningless
+// across calls.
+func recurringKey(recurring *bool) string {
+	if recurring == nil {
+		return ""
+	}
+	return fmt.Sprintf("%t", *recurring)
+}
+
+// Task serves id from the cache when present, falling back to and then
+// repopulating from the wrapped store on a miss or a Redis error.
+func (s *Store) Task(ctx context.Context, id int64) (storage.Task, error) {
+	key, ok := s.key(ctx, "task", fmt.Sprintf("%d", id))
+	if ok {
+		var t storage.Task
+		if s.get(ctx, key, &t) {
+			return t, nil
+		}
+	}
+
+	t, err := s.Store.Task(ctx, id)
+	if err != nil {
+		return storage.Task{}, err
+	}
+	if ok {
+		s.set(ctx, key, t)
+	}
+	return t, nil
+}
+
+// key builds a cache key namespaced under the current generation. It
+// returns ok = false, leaving the caller to bypass the cache, if Redis
+// can't be reached to read the generation.
+func (s *Store) key(ctx context.Context, kind, rest string) (key string, ok bool) {
+	gen, err := s.redis.Get(ctx, generationKey).Int64()
+	if err != nil && err != redis.Nil {
+		log.Printf("taskcache: read generation: %v", err)
+		return "", false
+	}
+	return fmt.Sprintf("tasks:%s:%d:%s", kind, gen, rest), true
+}
+
+func (s *Store) get(ctx context.Context, key string, dest any) bool {
+	cached, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("taskcache: read %s: %v", key, err)
+		}
+		return false
+	}
+	if err := json.Unmarshal(cached, dest); err != nil {
+		log.Printf("taskcache: decode %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (s *Store) set(ctx context.Context, key string, v any) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("taskcache: encode %s: %v", key, err)
+		return
+	}
+	if err := s.redis.Set(ctx, key, encoded, s.ttl).Err(); err != nil {
+		log.Printf("taskcache: write %s: %v", key, err)
+	}
+}
+
+// Close unsubscribes from the event bus and closes the Redis client.
+// It does not close the wrapped store, which callers typically close
+// separately.
+func (s *Store) Close() error {
+	s.unsubscribe()
+	return s.redis.Close()
+}
@@ -0,0 +1,148 @@
+// Package upgrade lets the server hand its listening socket to a freshly
+// exec'd copy of itself, so a binary upgrade or config reload doesn't drop
+// connections. This matters for single-instance self-hosters who have no
+// load balancer to shift traffic to while the process restarts.
+package upgrade
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// listenFDEnv names the environment variable a re-exec'd process reads to
+// find its inherited listening socket, passed as an extra file descriptor.
+const listenFDEnv = "TODO_LISTEN_FD"
+
+// inheritedFD is the fd number ExtraFiles always assigns the first passed
+// file in the child process (0, 1, 2 are stdin/stdout/stderr).
+const inheritedFD = 3
+
+// sdListenFDsStart is the fd number systemd's socket activation protocol
+// always assigns the first passed socket (SD_LISTEN_FDS_START).
+const sdListenFDsStart = 3
+
+// fileListener is satisfied by both *net.TCPListener and *net.UnixListener,
+// letting Listen and Spawn work with either without caring which.
+type fileListener interface {
+	net.Listener
+	File() (*os.File, error)
+}
+
+// Listen returns a listener bound to address on network ("tcp" or "unix"),
+// or, if this process was started by Spawn, the listener inherited from
+// its parent instead - binding to the same socket without a rebind gap.
+// It also recognizes systemd socket activation (LISTEN_PID/LISTEN_FDS set
+// for this process), inheriting the socket systemd bound instead of
+// binding its own - the unit's .socket file owns address in that case.
+// For network "unix", socketMode (if non-zero) is applied to the socket
+// file's permissions after binding.
+func Listen(network, address string, socketMode os.FileMode) (fileListener, error) {
+	if fln, ok, err := listenFromSystemd(); ok || err != nil {
+		return fln, err
+	}
+
+	if v := os.Getenv(listenFDEnv); v != "" {
+		fd, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", listenFDEnv, err)
+		}
+		f := os.NewFile(uintptr(fd), "inherited-listener")
+		ln, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("inherit listener: %w", err)
+		}
+		f.Close()
+		fln, ok := ln.(fileListener)
+		if !ok {
+			return nil, fmt.Errorf("inherited fd %d is not a TCP or Unix listener", fd)
+		}
+		return fln, nil
+	}
+
+	if network == "unix" {
+		// A stale socket file from an unclean shutdown would otherwise
+		// make the bind fail with "address already in use".
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	fln, ok := ln.(fileListener)
+	if !ok {
+		return nil, fmt.Errorf("unsupported listener network %q", network)
+	}
+	if network == "unix" && socketMode != 0 {
+		if err := os.Chmod(address, socketMode); err != nil {
+			fln.Close()
+			return nil, fmt.Errorf("chmod socket: %w", err)
+		}
+	}
+	return fln, nil
+}
+
+// listenFromSystemd returns the listener systemd passed this process via
+// socket activation, if any. LISTEN_PID must match this process (systemd
+// sets it to the direct child it exec'd, so a stale value from the
+// environment of a process that then re-exec'd itself is ignored).
+func listenFromSystemd() (fileListener, bool, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pid == "" || fds == "" {
+		return nil, false, nil
+	}
+	if want, err := strconv.Atoi(pid); err != nil || want != os.Getpid() {
+		return nil, false, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, false, fmt.Errorf("parse LISTEN_FDS: %q", fds)
+	}
+
+	f := os.NewFile(uintptr(sdListenFDsStart), "systemd-listener")
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, true, fmt.Errorf("inherit systemd listener: %w", err)
+	}
+	f.Close()
+	fln, ok := ln.(fileListener)
+	if !ok {
+		return nil, true, fmt.Errorf("systemd socket is not a TCP or Unix listener")
+	}
+	return fln, true, nil
+}
+
+// Spawn re-executes the current binary with the same arguments and
+// environment, handing it ln's underlying socket so it can start accepting
+// connections immediately. The caller is responsible for closing its own
+// copy of ln (typically via a graceful http.Server.Shutdown) once Spawn
+// returns successfully - the child now owns the listener.
+func Spawn(ln fileListener) (*os.Process, error) {
+	lnFile, err := ln.File()
+	if err != nil {
+		return nil, fmt.Errorf("duplicate listener fd: %w", err)
+	}
+	defer lnFile.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("resolve executable: %w", err)
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{lnFile}
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", listenFDEnv, inheritedFD))
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start new process: %w", err)
+	}
+	return cmd.Process, nil
+}
@@ -0,0 +1,16 @@
+// Package version holds build metadata set at compile time via -ldflags,
+// so a running server can report exactly which build it is.
+package version
+
+// Version, Commit and Date are overridden at build time, e.g.:
+//
+//	go build -ldflags "-X 'github.com/ngavrilenkov/go_final_project/internal/version.Version=v1.2.3' \
+//	  -X 'github.com/ngavrilenkov/go_final_project/internal/version.Commit=$(git rev-parse --short HEAD)' \
+//	  -X 'github.com/ngavrilenkov/go_final_project/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)'"
+//
+// Left unset, they default to "dev"/"unknown" for local builds.
+var (
+	Version = "dev"
+	Commit  = "unknown"
+	Date    = "unknown"
+)
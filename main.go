@@ -0,0 +1,55 @@
+// Command go_final_project is the scheduler's binary: it runs the HTTP
+// server (the "serve" subcommand, also the default with no subcommand
+// given) alongside a handful of operational subcommands - migrate, db,
+// export, import, archive, user, nextdate, backup and bench - that
+// operate on the same SQLite database and configuration without going
+// through the API.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	args := os.Args[1:]
+	cmd := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	if cmd == "serve" {
+		runServe(args)
+		return
+	}
+
+	var err error
+	switch cmd {
+	case "migrate":
+		err = runMigrate(args)
+	case "db":
+		err = runDB(args)
+	case "export":
+		err = runExport(args)
+	case "import":
+		err = runImport(args)
+	case "archive":
+		err = runArchive(args)
+	case "user":
+		err = runUser(args)
+	case "nextdate":
+		err = runNextDate(args)
+	case "backup":
+		err = runBackup(args)
+	case "bench":
+		err = runBench(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\nusage: %s [serve|migrate|db|export|import|archive|user|nextdate|backup|bench] [flags]\n", cmd, os.Args[0])
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
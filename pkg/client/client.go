@@ -0,0 +1,275 @@
+// Package client is a typed Go SDK for the scheduler's HTTP API, so other
+// Go programs can script the server (sign in, list and manage tasks)
+// without hand-rolling HTTP calls.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Task mirrors the scheduler's task representation. ID is a decimal
+// string, matching the JSON shape the API itself uses.
+type Task struct {
+	ID      string `json:"id,omitempty"`
+	Date    string `json:"date"`
+	Title   string `json:"title"`
+	Comment string `json:"comment"`
+	Repeat  string `json:"repeat"`
+}
+
+// Error is returned when the API responds with a non-2xx status.
+type Error struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("scheduler api: unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("scheduler api: %s (status %d, request %s)", e.Message, e.StatusCode, e.RequestID)
+}
+
+// retryDelay is how long Client waits between retry attempts.
+const retryDelay = 250 * time.Millisecond
+
+// Client calls the scheduler's HTTP API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	token      string
+}
+
+// Option configures a Client constructed with New.
+type Option func(*Client)
+
+// WithHTTPClient overrides the http.Client used for requests, e.g. to set
+// a timeout or a custom transport. The default is http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithMaxRetries sets how many additional attempts a request gets after a
+// network error or 5xx response, with a short delay between attempts.
+// The default is 2.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithToken sets the bearer token attached to every request, letting
+// callers reuse a session obtained outside of Login (e.g. a personal
+// access token).
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// New returns a Client for the scheduler running at baseURL, e.g.
+// "http://localhost:7540", or including a base path if the server is
+// mounted under one, e.g. "http://localhost:7540/todo".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		maxRetries: 2,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token returns the access token currently attached to requests, e.g. to
+// persist it for reuse in a later process (see WithToken).
+func (c *Client) Token() string {
+	return c.token
+}
+
+// Login signs in as the owner (password) or, if username is non-empty,
+// as a collaborator the owner shared the task list with, and stores the
+// returned access token on the client for use by subsequent calls.
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	req := map[string]string{"password": password}
+	if username != "" {
+		req["username"] = username
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/api/signin", req, &resp); err != nil {
+		return err
+	}
+	c.token = resp.Token
+	return nil
+}
+
+// AddTask creates a new task and returns its id.
+func (c *Client) AddTask(ctx context.Context, t Task) (string, error) {
+	var resp struct {
+		ID json.Number `json:"id"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/api/task/", t, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID.String(), nil
+}
+
+// GetTasks lists tasks, most recent first, optionally filtered by
+// search - either free text matched against the title and comment, or an
+// exact date in DD.MM.YYYY format.
+func (c *Client) GetTasks(ctx context.Context, search string) ([]Task, error) {
+	path := "/api/tasks"
+	if search != "" {
+		path += "?" + url.Values{"search": {search}}.Encode()
+	}
+	var resp struct {
+		Tasks []Task `json:"tasks"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
+// GetTask fetches a single task by id.
+func (c *Client) GetTask(ctx context.Context, id string) (Task, error) {
+	var t Task
+	path := "/api/task/?" + url.Values{"id": {id}}.Encode()
+	err := c.doJSON(ctx, http.MethodGet, path, nil, &t)
+	return t, err
+}
+
+// UpdateTask replaces an existing task's fields; t.ID must name the task
+// to update.
+func (c *Client) UpdateTask(ctx context.Context, t Task) error {
+	return c.doJSON(ctx, http.MethodPut, "/api/task/", t, nil)
+}
+
+// DeleteTask removes a task by id.
+func (c *Client) DeleteTask(ctx context.Context, id string) error {
+	path := "/api/task/?" + url.Values{"id": {id}}.Encode()
+	return c.doJSON(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// DoTask marks a task done: a one-off task is deleted, a repeating task
+// is advanced to its next occurrence.
+func (c *Client) DoTask(ctx context.Context, id string) error {
+	path := "/api/task/done?" + url.Values{"id": {id}}.Encode()
+	return c.doJSON(ctx, http.MethodPost, path, nil, nil)
+}
+
+// NextDate computes the next occurrence of repeat strictly after now,
+// starting from date. now and date use the scheduler's YYYYMMDD format.
+func (c *Client) NextDate(ctx context.Context, now, date, repeat string) (string, error) {
+	q := url.Values{"now": {now}, "date": {date}, "repeat": {repeat}}
+	body, err := c.doRaw(ctx, http.MethodGet, "/api/nextdate?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// doJSON sends a JSON request (skipped if body is nil) and, if out is
+// non-nil, decodes the JSON response into it.
+func (c *Client) doJSON(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	respBody, err := c.doRaw(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// doRaw sends a request and returns its body, retrying on network errors
+// and 5xx responses up to maxRetries times. body, if non-nil, must
+// support being read more than once across retries only when it's a
+// *bytes.Reader, which is all this package ever passes.
+func (c *Client) doRaw(ctx context.Context, method, path string, body io.Reader) ([]byte, error) {
+	var bodyBytes []byte
+	if r, ok := body.(*bytes.Reader); ok {
+		bodyBytes = make([]byte, r.Len())
+		_, _ = r.ReadAt(bodyBytes, 0)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryDelay):
+			}
+		}
+
+		var reqBody io.Reader
+		if bodyBytes != nil {
+			reqBody = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("build request: %w", err)
+		}
+		if bodyBytes != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("do request: %w", err)
+			continue
+		}
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			lastErr = fmt.Errorf("read response: %w", readErr)
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = apiError(resp.StatusCode, respBody)
+			continue
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			return nil, apiError(resp.StatusCode, respBody)
+		}
+		return respBody, nil
+	}
+	return nil, lastErr
+}
+
+// apiError turns a non-2xx response into an *Error, decoding the
+// scheduler's {"error", "request_id"} envelope when present.
+func apiError(statusCode int, body []byte) *Error {
+	var envelope struct {
+		Error     string `json:"error"`
+		RequestID string `json:"request_id"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+	return &Error{StatusCode: statusCode, Message: envelope.Error, RequestID: envelope.RequestID}
+}
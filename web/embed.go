@@ -0,0 +1,9 @@
+// Package web embeds the static UI's assets into the server binary, so it
+// runs self-contained regardless of the working directory it's started
+// from. See config.WebDir for the on-disk override used in development.
+package web
+
+import "embed"
+
+//go:embed css js index.html login.html favicon.ico
+var FS embed.FS